@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+	"github.com/likecoin/iscn-ipld/plugin/iscn"
+	"github.com/likecoin/iscn-ipld/plugin/server"
+	"github.com/likecoin/iscn-ipld/plugin/store"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [options]\n\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "Commands:\n")
+	fmt.Fprintf(os.Stderr, "  serve    serve the ISCN HTTP API\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "serve":
+		serve(os.Args[2:])
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+func serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "127.0.0.1:8080", "address to listen on")
+	dir := flags.String("store", "", "directory of the flat-file blockstore, blocks are kept in memory if empty")
+	custom := flags.String("custom", "permissive", "policy of custom properties: permissive, strict or namespaced:<prefix>,...")
+	didDocuments := flags.String("did-documents", "", "directory of the DID documents resolving did:web and did:cosmos")
+	flags.Parse(args)
+
+	r := block.NewRegistry()
+	iscn.RegisterTo(r)
+
+	policy, err := parseCustomPolicy(*custom)
+	if err != nil {
+		log.Fatal(err)
+	}
+	r.SetCustomPolicy(policy)
+
+	if *didDocuments != "" {
+		resolver := did.NewFileResolver(*didDocuments)
+		did.RegisterResolver("web", resolver)
+		did.RegisterResolver("cosmos", resolver)
+	}
+
+	var bs store.Blockstore
+	if *dir == "" {
+		bs = store.NewMemoryBlockstore()
+	} else {
+		fs, err := store.NewFlatFileBlockstore(*dir)
+		if err != nil {
+			log.Fatal(err)
+		}
+		bs = fs
+	}
+
+	log.Printf("Serving ISCN HTTP API on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, server.New(r, bs)))
+}
+
+func parseCustomPolicy(s string) (block.CustomPolicy, error) {
+	mode := s
+	prefixes := []string{}
+	if i := strings.Index(s, ":"); i >= 0 {
+		mode = s[:i]
+		for _, prefix := range strings.Split(s[i+1:], ",") {
+			if prefix != "" {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+
+	switch mode {
+	case "permissive":
+		return block.PermissivePolicy(), nil
+	case "strict":
+		return block.StrictPolicy(), nil
+	case "namespaced":
+		return block.NamespacedPolicy(prefixes...), nil
+	}
+
+	return block.CustomPolicy{}, fmt.Errorf("Unknown custom property policy %q", s)
+}
@@ -3,12 +3,16 @@ module github.com/likecoin/iscn-ipld
 go 1.13
 
 require (
+	github.com/btcsuite/btcd v0.20.1-beta
 	github.com/btcsuite/btcutil v0.0.0-20190425235716-9e5f4b9a998d
 	github.com/ipfs/go-block-format v0.0.2
 	github.com/ipfs/go-cid v0.0.5
 	github.com/ipfs/go-ipfs v0.5.0
 	github.com/ipfs/go-ipld-cbor v0.0.4
 	github.com/ipfs/go-ipld-format v0.2.0
+	github.com/multiformats/go-multibase v0.0.2
 	github.com/multiformats/go-multihash v0.0.13
 	gitlab.com/c0b/go-ordered-json v0.0.0-20171130231205-49bbdab258c2
+	golang.org/x/crypto v0.0.0-20200423211502-4bdfaf469ed5
+	lukechampine.com/blake3 v1.1.7
 )
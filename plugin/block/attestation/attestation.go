@@ -0,0 +1,195 @@
+package attestation
+
+import (
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+)
+
+const (
+	// SchemaName of attestation
+	SchemaName = "attestation"
+)
+
+// Register registers the schema of attestation block to the default registry
+func Register() {
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers the schema of attestation block to the registry
+func RegisterTo(r *block.Registry) {
+	r.Register(
+		block.CodecAttestation,
+		SchemaName,
+		newSchemaV1,
+	)
+}
+
+// ==================================================
+// base
+// ==================================================
+
+// base is the base struct for attestation (codec 0x0269)
+type base struct {
+	*block.Base
+}
+
+func newBase(version uint64, schema []data.Data) (*base, error) {
+	blockBase, err := block.NewBase(
+		block.CodecAttestation,
+		SchemaName,
+		version,
+		schema,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &base{
+		Base: blockBase,
+	}, nil
+}
+
+// ==================================================
+// schemaV1
+// ==================================================
+
+// schemaV1 represents an attestation V1, which is a detached signature of
+// the subject block by a key of the signer entity
+type schemaV1 struct {
+	*base
+
+	subject   *data.Cid
+	signer    *data.Cid
+	key       *PublicKey
+	signature *Signature
+	timestamp *data.Timestamp
+}
+
+var _ block.IscnObject = (*schemaV1)(nil)
+
+func newSchemaV1() (block.Codec, error) {
+	subject := data.NewLink("subject", true, 0)
+	signer := data.NewLink("signer", true, block.CodecEntity)
+	key := NewPublicKey("key", true)
+	signature := NewSignature("signature", true)
+	timestamp := data.NewTimestamp("timestamp", false)
+
+	schema := []data.Data{
+		subject,
+		signer,
+		key,
+		signature,
+		timestamp,
+	}
+
+	attestationBase, err := newBase(1, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV1{
+		base:      attestationBase,
+		subject:   subject,
+		signer:    signer,
+		key:       key,
+		signature: signature,
+		timestamp: timestamp,
+	}
+	attestationBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// Validate the data, the signature should be made by the key
+func (o *schemaV1) Validate() error {
+	subject, err := o.subject.Link()
+	if err != nil {
+		return err
+	}
+
+	signer, err := o.signer.Link()
+	if err != nil {
+		return err
+	}
+
+	timestamp := ""
+	if o.timestamp.IsDefined() {
+		timestamp = o.timestamp.Get()
+	}
+
+	payload, err := Payload(subject.Cid, signer.Cid, o.key.GetPublicKey(), timestamp)
+	if err != nil {
+		return err
+	}
+
+	if !o.key.GetPublicKey().Verify(payload, o.signature.GetSignature()) {
+		return fmt.Errorf("Attestation: the signature is not made by the key")
+	}
+
+	return nil
+}
+
+// attestation returns the values of the attestation
+func (o *schemaV1) attestation() (*Attestation, error) {
+	subject, err := o.subject.Link()
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := o.signer.Link()
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Attestation{
+		Cid:       o.Cid(),
+		Subject:   subject.Cid,
+		Signer:    signer.Cid,
+		Key:       o.key.GetPublicKey(),
+		Signature: o.signature.GetSignature(),
+	}
+
+	if o.timestamp.IsDefined() {
+		res.Timestamp = o.timestamp.Get()
+	}
+
+	return res, nil
+}
+
+// ==================================================
+// Attestation
+// ==================================================
+
+// Attestation is a detached signature of an ISCN block
+type Attestation struct {
+	// Cid is the CID of the attestation block
+	Cid cid.Cid
+
+	// Subject is the CID of the signed block
+	Subject cid.Cid
+
+	// Signer is the CID of the entity on whose behalf the block is signed
+	Signer cid.Cid
+
+	Key       *did.PublicKey
+	Signature []byte
+
+	// Timestamp is the optional ISO 8601 time of signing
+	Timestamp string
+}
+
+// Parse returns the values of the attestation block
+func Parse(obj block.IscnObject) (*Attestation, error) {
+	o, ok := obj.(interface {
+		attestation() (*Attestation, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("Attestation: %s is not an attestation", obj)
+	}
+
+	return o.attestation()
+}
@@ -0,0 +1,244 @@
+package attestation_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/attestation"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+	"github.com/likecoin/iscn-ipld/plugin/iscn"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+func init() {
+	iscn.Register()
+}
+
+type fixture struct {
+	subject cid.Cid
+	objs    map[cid.Cid]block.IscnObject
+}
+
+func newFixture(t *testing.T) *fixture {
+	h, err := mh.Sum([]byte("stakeholders"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &fixture{
+		subject: cid.NewCidV1(block.CodecStakeholders, h),
+		objs:    map[cid.Cid]block.IscnObject{},
+	}
+}
+
+func (f *fixture) Load(c cid.Cid) (block.IscnObject, error) {
+	obj, ok := f.objs[c]
+	if !ok {
+		return nil, fmt.Errorf("%s is not found", c)
+	}
+	return obj, nil
+}
+
+func (f *fixture) entity(t *testing.T, m map[string]interface{}) cid.Cid {
+	obj, err := block.Encode(block.CodecEntity, 4, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.objs[obj.Cid()] = obj
+	return obj.Cid()
+}
+
+func secp256k1Signer(t *testing.T, b byte) did.Signer {
+	s, err := did.NewSecp256k1Signer(bytes.Repeat([]byte{b}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func ed25519Signer(t *testing.T, b byte) did.Signer {
+	s, err := did.NewEd25519Signer(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{b}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func chainID(t *testing.T, s did.Signer) string {
+	address, err := s.PublicKey().Bech32Address("cosmos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "lcc://id/" + address
+}
+
+func sign(t *testing.T, subject cid.Cid, entity cid.Cid, s did.Signer) *attestation.Attestation {
+	obj, err := attestation.Sign(subject, entity, s, "2020-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := attestation.Parse(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestVerifySigner(t *testing.T) {
+	f := newFixture(t)
+
+	owner := secp256k1Signer(t, 1)
+	didOwner := ed25519Signer(t, 2)
+	forger := secp256k1Signer(t, 3)
+	didForger := ed25519Signer(t, 4)
+
+	byAddress := f.entity(t, map[string]interface{}{"id": chainID(t, owner)})
+	byDID := f.entity(t, map[string]interface{}{"did": didOwner.PublicKey().DIDKey()})
+
+	cases := []struct {
+		name   string
+		entity cid.Cid
+		signer did.Signer
+		valid  bool
+	}{
+		{"address owner", byAddress, owner, true},
+		{"DID owner", byDID, didOwner, true},
+		{"forged secp256k1 key of address", byAddress, forger, false},
+		{"forged ed25519 key of address", byAddress, didForger, false},
+		{"forged ed25519 key of DID", byDID, didForger, false},
+		{"forged secp256k1 key of DID", byDID, forger, false},
+	}
+
+	for _, c := range cases {
+		a := sign(t, f.subject, c.entity, c.signer)
+		err := a.VerifySigner(f, did.DefaultResolver)
+		if c.valid && err != nil {
+			t.Errorf("%s: %s", c.name, err)
+		} else if !c.valid && err == nil {
+			t.Errorf("%s: the forged key should be rejected", c.name)
+		}
+	}
+}
+
+// A signature made by another key than the one in the attestation, or of
+// another payload, cannot be encoded or decoded
+func TestForgedSignature(t *testing.T) {
+	f := newFixture(t)
+	owner := secp256k1Signer(t, 1)
+	forger := secp256k1Signer(t, 3)
+	entity := f.entity(t, map[string]interface{}{"id": chainID(t, owner)})
+
+	payload, err := attestation.Payload(f.subject, entity, owner.PublicKey(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := forger.Sign(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := map[string]interface{}{
+		"subject":   f.subject,
+		"signer":    entity,
+		"key":       owner.PublicKey().Multibase(),
+		"signature": signature,
+	}
+
+	if _, err := block.Encode(block.CodecAttestation, 1, m); err == nil {
+		t.Errorf("Encode: the signature by another key should be rejected")
+	}
+
+	// The signature by the key in the malleable high-S form
+	signature, err = owner.Sign(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m["signature"] = signature
+	obj, err := block.Encode(block.CodecAttestation, 1, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := new(big.Int).Sub(btcec.S256().N, new(big.Int).SetBytes(signature[32:]))
+	malleable := append([]byte{}, signature[:32]...)
+	malleable = append(malleable, make([]byte, 32-len(s.Bytes()))...)
+	malleable = append(malleable, s.Bytes()...)
+
+	m["signature"] = malleable
+	if _, err := block.Encode(block.CodecAttestation, 1, m); err == nil {
+		t.Errorf("Encode: the high-S signature should be rejected")
+	}
+
+	// A tampered block with the original CID
+	raw := bytes.Replace(obj.RawData(), signature, malleable, 1)
+	if _, err := block.Decode(raw, obj.Cid()); err == nil {
+		t.Errorf("Decode: the tampered block should be rejected")
+	}
+}
+
+func TestVerifyStakeholders(t *testing.T) {
+	f := newFixture(t)
+	creator := secp256k1Signer(t, 1)
+	editor := ed25519Signer(t, 2)
+	outsider := secp256k1Signer(t, 3)
+
+	creatorID := f.entity(t, map[string]interface{}{"id": chainID(t, creator)})
+	editorID := f.entity(t, map[string]interface{}{"did": editor.PublicKey().DIDKey()})
+	outsiderID := f.entity(t, map[string]interface{}{"id": chainID(t, outsider)})
+
+	stakeholders, err := block.Encode(block.CodecStakeholders, 3, map[string]interface{}{
+		"stakeholders": []interface{}{
+			map[string]interface{}{"type": "Creator", "stakeholder": creatorID, "sharing": 70},
+			map[string]interface{}{"type": "Editor", "stakeholder": editorID, "sharing": 30},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attestations := []*attestation.Attestation{
+		sign(t, stakeholders.Cid(), creatorID, creator),
+		sign(t, stakeholders.Cid(), outsiderID, outsider),
+		sign(t, stakeholders.Cid(), editorID, outsider),
+		sign(t, f.subject, editorID, editor),
+	}
+
+	report, err := attestation.VerifyStakeholders(stakeholders, attestations, f, did.DefaultResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := report.Signed[creatorID]; !ok || len(report.Signed) != 1 {
+		t.Errorf("Signed: only the creator is expected but %v is found", report.Signed)
+	}
+
+	if len(report.Rejected) != 3 {
+		t.Errorf("Rejected: 3 are expected but %d is found", len(report.Rejected))
+	}
+
+	pending := report.Pending()
+	if len(pending) != 1 || !pending[0].Equals(editorID) || report.IsComplete() {
+		t.Errorf("Pending: the editor is expected but %v is found", pending)
+	}
+
+	attestations = append(attestations, sign(t, stakeholders.Cid(), editorID, editor))
+	report, err = attestation.VerifyStakeholders(stakeholders, attestations, f, did.DefaultResolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !report.IsComplete() {
+		t.Errorf("IsComplete: all the stakeholders have signed but %v is pending", report.Pending())
+	}
+}
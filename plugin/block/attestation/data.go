@@ -0,0 +1,201 @@
+package attestation
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+	"gitlab.com/c0b/go-ordered-json"
+)
+
+// ==================================================
+// PublicKey
+// ==================================================
+
+// PublicKey is a data handler for the public key of the signer in the
+// multibase form of did:key, e.g. "z6Mk..."
+type PublicKey struct {
+	*data.Base
+
+	value *data.String
+	key   *did.PublicKey
+}
+
+var _ data.Data = (*PublicKey)(nil)
+
+// NewPublicKey creates a public key data handler
+func NewPublicKey(key string, isRequired bool) *PublicKey {
+	return &PublicKey{
+		Base:  data.NewBase(key, isRequired),
+		value: data.NewString("", false),
+	}
+}
+
+// Prototype creates a prototype PublicKey
+func (d *PublicKey) Prototype() data.Data {
+	return &PublicKey{
+		Base:  d.Base.Prototype(),
+		value: data.NewString("", false),
+	}
+}
+
+// Get returns the public key in the multibase form
+func (d *PublicKey) Get() string {
+	return d.value.Get()
+}
+
+// GetPublicKey returns the public key
+func (d *PublicKey) GetPublicKey() *did.PublicKey {
+	return d.key
+}
+
+// Set the value of PublicKey
+func (d *PublicKey) Set(obj interface{}) error {
+	if err := d.value.Set(obj); err != nil {
+		return err
+	}
+
+	key, err := did.ParseMultibaseKey(d.value.Get())
+	if err != nil {
+		return err
+	}
+
+	// Store the normalized form so that the same key always produces the
+	// same block
+	if err := d.value.Set(key.Multibase()); err != nil {
+		return err
+	}
+
+	d.key = key
+	d.Base.MarkDefined()
+	return nil
+}
+
+// Encode PublicKey
+func (d *PublicKey) Encode() (interface{}, error) {
+	return d.value.Encode()
+}
+
+// Decode PublicKey
+func (d *PublicKey) Decode(obj interface{}) (interface{}, error) {
+	if err := d.Set(obj); err != nil {
+		return nil, err
+	}
+
+	d.Base.MarkDefined()
+	return d.value.Get(), nil
+}
+
+// ToJSON prepares the data for MarshalJSON
+func (d *PublicKey) ToJSON() (interface{}, error) {
+	return d.value.ToJSON()
+}
+
+// Resolve resolves the value
+func (d *PublicKey) Resolve(path []string) (interface{}, []string, error) {
+	return d.value.Resolve(path)
+}
+
+// KindPublicKey is the kind of attestation public key handler
+const KindPublicKey data.Kind = "publicKey"
+
+// Describe returns the descriptor of PublicKey
+func (d *PublicKey) Describe() *data.Descriptor {
+	return data.NewDescriptor(d.GetKey(), KindPublicKey, d.IsRequired())
+}
+
+// ==================================================
+// Signature
+// ==================================================
+
+// Signature is a data handler for the 64-byte signature, which is a byte
+// string in the DAG-JSON form {"/": {"bytes": "..."}} in JSON
+type Signature struct {
+	*data.Base
+
+	signature []byte
+}
+
+var _ data.Data = (*Signature)(nil)
+
+// NewSignature creates a signature data handler
+func NewSignature(key string, isRequired bool) *Signature {
+	return &Signature{
+		Base: data.NewBase(key, isRequired),
+	}
+}
+
+// Prototype creates a prototype Signature
+func (d *Signature) Prototype() data.Data {
+	return &Signature{
+		Base: d.Base.Prototype(),
+	}
+}
+
+// GetSignature returns the signature
+func (d *Signature) GetSignature() []byte {
+	return d.signature
+}
+
+// Set the value of Signature
+func (d *Signature) Set(obj interface{}) error {
+	signature, ok := obj.([]byte)
+	if !ok {
+		return fmt.Errorf("Signature: '[]byte' is expected but '%T' is found", obj)
+	}
+
+	if len(signature) != did.SignatureSize {
+		return fmt.Errorf(
+			"Signature: should length %d but %d is found",
+			did.SignatureSize,
+			len(signature),
+		)
+	}
+
+	d.signature = signature
+	d.Base.MarkDefined()
+	return nil
+}
+
+// Encode Signature
+func (d *Signature) Encode() (interface{}, error) {
+	return d.signature, nil
+}
+
+// Decode Signature
+func (d *Signature) Decode(obj interface{}) (interface{}, error) {
+	if err := d.Set(obj); err != nil {
+		return nil, err
+	}
+
+	d.Base.MarkDefined()
+	return d.signature, nil
+}
+
+// ToJSON prepares the data for MarshalJSON
+func (d *Signature) ToJSON() (interface{}, error) {
+	bytes := ordered.NewOrderedMap()
+	bytes.Set("bytes", base64.RawStdEncoding.EncodeToString(d.signature))
+
+	om := ordered.NewOrderedMap()
+	om.Set("/", bytes)
+	return om, nil
+}
+
+// Resolve resolves the value
+func (d *Signature) Resolve(path []string) (interface{}, []string, error) {
+	if len(path) != 0 {
+		return nil, nil, fmt.Errorf("Unexpected path elements past %s", path[0])
+	}
+
+	return d.signature, nil, nil
+}
+
+// KindSignature is the kind of attestation signature handler
+const KindSignature data.Kind = "signature"
+
+// Describe returns the descriptor of Signature
+func (d *Signature) Describe() *data.Descriptor {
+	return data.NewDescriptor(d.GetKey(), KindSignature, d.IsRequired())
+}
@@ -0,0 +1,209 @@
+package attestation
+
+import (
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/entity"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholders"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+)
+
+// ==================================================
+// Signing
+// ==================================================
+
+// payloadType separates the attestation payloads from the other messages
+// signed by the same keys
+const payloadType = "iscn-attestation"
+
+// Payload returns the message signed by the key for the attestation, which
+// is the canonical DAG-CBOR encoding of the type, the subject, the signer,
+// the key and the optional timestamp
+func Payload(subject cid.Cid, signer cid.Cid, key *did.PublicKey, timestamp string) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("Attestation: the key is missing")
+	}
+
+	m := map[string]interface{}{
+		"type":    payloadType,
+		"subject": subject,
+		"signer":  signer,
+		"key":     key.Multibase(),
+	}
+
+	if timestamp != "" {
+		m["timestamp"] = timestamp
+	}
+
+	return block.EncodeCanonical(m)
+}
+
+// Sign creates an attestation block of the subject signed by the signer on
+// behalf of the entity, the timestamp is optional
+func Sign(
+	subject cid.Cid,
+	signer cid.Cid,
+	s did.Signer,
+	timestamp string,
+) (block.IscnObject, error) {
+	payload, err := Payload(subject, signer, s.PublicKey(), timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := s.Sign(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]interface{}{
+		"subject":   subject,
+		"signer":    signer,
+		"key":       s.PublicKey().Multibase(),
+		"signature": signature,
+	}
+
+	if timestamp != "" {
+		m["timestamp"] = timestamp
+	}
+
+	return block.Encode(block.CodecAttestation, 1, m)
+}
+
+// ==================================================
+// Verification
+// ==================================================
+
+// VerifySignature verifies that the signature is made by the key, which is
+// also checked when the attestation block is decoded
+func (a *Attestation) VerifySignature() error {
+	payload, err := Payload(a.Subject, a.Signer, a.Key, a.Timestamp)
+	if err != nil {
+		return err
+	}
+
+	if !a.Key.Verify(payload, a.Signature) {
+		return fmt.Errorf("Attestation: the signature is not made by the key")
+	}
+
+	return nil
+}
+
+// VerifySigner verifies the signature and that the key belongs to the signer
+// entity, i.e. its LikeCoin chain ID or bech32 address is the address of the
+// key or the key is an assertion key of one of its DIDs
+func (a *Attestation) VerifySigner(loader block.Loader, resolver did.Resolver) error {
+	if err := a.VerifySignature(); err != nil {
+		return err
+	}
+
+	obj, err := loader.Load(a.Signer)
+	if err != nil {
+		return err
+	}
+
+	ok, err := entity.IsControlledBy(obj, a.Key, resolver)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return fmt.Errorf("Attestation: the key %s does not belong to the entity %s", a.Key.Multibase(), a.Signer)
+	}
+
+	return nil
+}
+
+// Rejection is an attestation which fails the verification
+type Rejection struct {
+	Attestation *Attestation
+	Err         error
+}
+
+// Report reports the consent of the entities listed in a stakeholders block
+type Report struct {
+	// Subject is the CID of the stakeholders block
+	Subject cid.Cid
+
+	// Entities are the entities listed in the stakeholders block in order
+	Entities []cid.Cid
+
+	// Signed are the verified attestations by the entities
+	Signed map[cid.Cid]*Attestation
+
+	// Rejected are the attestations which are not of the subject, not by the
+	// listed entities or fail the verification
+	Rejected []Rejection
+}
+
+// Pending returns the entities without a verified attestation in order
+func (r *Report) Pending() []cid.Cid {
+	res := []cid.Cid{}
+	for _, c := range r.Entities {
+		if _, ok := r.Signed[c]; !ok {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// IsComplete checks whether all the listed entities have signed
+func (r *Report) IsComplete() bool {
+	return len(r.Pending()) == 0
+}
+
+// VerifyStakeholders verifies the attestations of the stakeholders block
+// against the entities listed in it. The attestations which are not of the
+// stakeholders block, not by a listed entity or fail the verification are
+// rejected instead of failing the whole verification
+func VerifyStakeholders(
+	obj block.IscnObject,
+	attestations []*Attestation,
+	loader block.Loader,
+	resolver did.Resolver,
+) (*Report, error) {
+	entities, err := stakeholders.Entities(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Subject:  obj.Cid(),
+		Entities: entities,
+		Signed:   map[cid.Cid]*Attestation{},
+		Rejected: []Rejection{},
+	}
+
+	listed := map[cid.Cid]bool{}
+	for _, c := range entities {
+		listed[c] = true
+	}
+
+	for _, a := range attestations {
+		var err error
+		switch {
+		case !a.Subject.Equals(obj.Cid()):
+			err = fmt.Errorf("Attestation: the subject %s is not the stakeholders %s", a.Subject, obj.Cid())
+		case !listed[a.Signer]:
+			err = fmt.Errorf("Attestation: the signer %s is not a listed stakeholder", a.Signer)
+		default:
+			err = a.VerifySigner(loader, resolver)
+		}
+
+		if err != nil {
+			report.Rejected = append(report.Rejected, Rejection{
+				Attestation: a,
+				Err:         err,
+			})
+			continue
+		}
+
+		if _, ok := report.Signed[a.Signer]; !ok {
+			report.Signed[a.Signer] = a
+		}
+	}
+
+	return report, nil
+}
@@ -10,7 +10,6 @@ import (
 	"gitlab.com/c0b/go-ordered-json"
 
 	blocks "github.com/ipfs/go-block-format"
-	cbor "github.com/ipfs/go-ipld-cbor"
 	node "github.com/ipfs/go-ipld-format"
 	mh "github.com/multiformats/go-multihash"
 )
@@ -51,6 +50,8 @@ type Codec interface {
 
 	MarkNested()
 
+	GetSchema() []data.Data
+
 	GetData() (*ordered.OrderedMap, error)
 	SetData(map[string]interface{}) error
 
@@ -61,118 +62,60 @@ type Codec interface {
 // CodecFactoryFunc returns a factory function to create ISCN object
 type CodecFactoryFunc func() (Codec, error)
 
-type codecFactory map[uint64][]CodecFactoryFunc
-
-var factory codecFactory = codecFactory{}
-var schemaNames map[uint64]string = map[uint64]string{}
-
 // Validator is a validate function for post validation after set data in block
 type Validator func() error
 
-// RegisterIscnObjectFactory registers an array of ISCN object factory functions
+// RegisterIscnObjectFactory registers an array of ISCN object factory
+// functions to the default registry
 func RegisterIscnObjectFactory(
 	codec uint64,
 	schemaName string,
 	factories ...CodecFactoryFunc,
 ) {
-	factory[codec] = factories
-	schemaNames[codec] = schemaName
+	DefaultRegistry.Register(codec, schemaName, factories...)
+}
+
+// LookupCodec returns the codec of the schema name in the default registry
+func LookupCodec(schemaName string) (uint64, bool) {
+	return DefaultRegistry.LookupCodec(schemaName)
+}
+
+// LatestVersion returns the latest version of the codec in the default
+// registry
+func LatestVersion(codec uint64) (uint64, error) {
+	return DefaultRegistry.LatestVersion(codec)
+}
+
+// Codecs returns all codecs of the default registry in ascending order
+func Codecs() []uint64 {
+	return DefaultRegistry.Codecs()
+}
+
+// New creates an empty ISCN object of specific codec and version from the
+// default registry
+func New(codec uint64, version uint64) (Codec, error) {
+	return DefaultRegistry.New(codec, version)
 }
 
-// Encode the data to specific ISCN object and version
+// Encode the data to specific ISCN object and version of the default registry
 func Encode(
 	codec uint64,
 	version uint64,
 	m map[string]interface{},
 ) (IscnObject, error) {
-	schemas, ok := factory[codec]
-	if !ok {
-		return nil, fmt.Errorf("Codec 0x%x is not registered", codec)
-	}
-
-	if version > (uint64)(len(schemas)) {
-		return nil, fmt.Errorf("<%s (v%d)> is not implemented", schemaNames[codec], version)
-	}
-	version--
-
-	obj, err := schemas[version]()
-	if err != nil {
-		return nil, err
-	}
-
-	if err := obj.SetData(m); err != nil {
-		return nil, err
-	}
-
-	if _, err := obj.Encode(); err != nil {
-		return nil, err
-	}
-
-	return obj, nil
+	return DefaultRegistry.Encode(codec, version, m)
 }
 
-// DecodeBlock decodes the raw IPLD data back to data object
+// DecodeBlock decodes the raw IPLD data back to data object with the default
+// registry
 func DecodeBlock(block blocks.Block) (node.Node, error) {
-	return Decode(block.RawData(), block.Cid())
+	return DefaultRegistry.DecodeBlock(block)
 }
 
-// Decode decodes the raw IPLD data back to data object
+// Decode decodes the raw IPLD data back to data object with the default
+// registry
 func Decode(rawData []byte, c cid.Cid) (IscnObject, error) {
-	rawObj := map[string]interface{}{}
-	if err := cbor.DecodeInto(rawData, &rawObj); err != nil {
-		return nil, err
-	}
-
-	v, ok := rawObj[data.ContextKey]
-	if !ok {
-		return nil, fmt.Errorf("Invalid ISCN IPLD object, missing context")
-	}
-
-	version, ok := v.(uint64)
-	if !ok {
-		return nil, fmt.Errorf("Context: 'uint64' is expected but '%T' is found", v)
-	}
-
-	schemas, ok := factory[c.Type()]
-	if !ok {
-		return nil, fmt.Errorf("Codec 0x%x is not registered", c.Type())
-	}
-
-	if version > (uint64)(len(schemas)) {
-		return nil, fmt.Errorf("<%s (v%d)> is not implemented", schemaNames[c.Type()], version)
-	}
-	version--
-
-	obj, err := schemas[version]()
-	if err != nil {
-		return nil, err
-	}
-
-	if err := obj.Decode(rawObj); err != nil {
-		return nil, err
-	}
-
-	// Encode one more time to retrieve CID
-	if _, err := obj.Encode(); err != nil {
-		return nil, err
-	}
-
-	// Verify the CID
-	if !obj.Cid().Equals(c) {
-		current, err := obj.Cid().StringOfBase('z')
-		if err != nil {
-			return nil, fmt.Errorf("Cannot retrieve current CID")
-		}
-
-		expected, err := c.StringOfBase('z')
-		if err != nil {
-			return nil, fmt.Errorf("Cannot retrieve expected CID")
-		}
-
-		return nil, fmt.Errorf("Cid %q is not matched: expected %q", current, expected)
-	}
-
-	return obj, nil
+	return DefaultRegistry.Decode(rawData, c)
 }
 
 const (
@@ -183,17 +126,18 @@ const (
 	domainLikeCoin = "likecoin"
 )
 
-func getSchema(codec uint64) string {
+func getSchema(codec uint64, name string) string {
 	switch codec {
 	case CodecISCN,
 		CodecRights,
 		CodecStakeholders,
 		CodecContent,
 		CodecEntity,
+		CodecAttestation,
 		CodecRight,
 		CodecStakeholder,
 		CodecTimePeriod:
-		return fmt.Sprintf("https://%s/%s", domainIscn, schemaNames[codec])
+		return fmt.Sprintf("https://%s/%s", domainIscn, name)
 	}
 
 	panic(fmt.Sprintf("Unknown codec 0x%x", codec))
@@ -216,6 +160,10 @@ type Base struct {
 	custom    map[string]interface{}
 	validator Validator
 
+	// customCheck checks the custom properties against the custom property
+	// policy and the extensions of the registry creating the object
+	customCheck func(map[string]interface{}) (map[string]interface{}, error)
+
 	cid     *cid.Cid
 	rawData []byte
 }
@@ -238,7 +186,7 @@ func NewBase(codec uint64, name string, version uint64, schema []data.Data) (*Ba
 	}
 
 	// Set "context" data
-	context := data.NewContext(getSchema(codec))
+	context := data.NewContext(getSchema(codec, name))
 	err := context.Set(version)
 	if err != nil {
 		return nil, err
@@ -267,6 +215,19 @@ func (b *Base) GetVersion() uint64 {
 	return b.version
 }
 
+// GetSchema returns the data handlers of the schema in order, the context is
+// excluded
+func (b *Base) GetSchema() []data.Data {
+	schema := []data.Data{}
+	for _, key := range b.keys {
+		if key != data.ContextKey {
+			schema = append(schema, b.data[key])
+		}
+	}
+
+	return schema
+}
+
 // GetCustom returns the custom data
 func (b *Base) GetCustom() map[string]interface{} {
 	return b.custom
@@ -439,6 +400,10 @@ func (b *Base) MarkNested() {
 	b.isNested = true
 }
 
+func (b *Base) setCustomCheck(check func(map[string]interface{}) (map[string]interface{}, error)) {
+	b.customCheck = check
+}
+
 // GetData returns the block data as ordered.OrderedMap
 func (b *Base) GetData() (*ordered.OrderedMap, error) {
 	om := ordered.NewOrderedMap()
@@ -466,15 +431,26 @@ func (b *Base) GetData() (*ordered.OrderedMap, error) {
 		om.Set(handler.GetKey(), value)
 	}
 
-	for key, value := range b.custom {
-		om.Set(key, value)
+	// Custom data is presented in the DAG-JSON form in a stable order
+	for _, key := range sortedKeys(b.custom) {
+		om.Set(key, customToJSON(b.custom[key]))
 	}
 
 	return om, nil
 }
 
-// SetData sets and validates the data
+// SetData sets and validates the data, the custom properties of an object
+// created by a registry are checked against its custom property policy and
+// extensions
 func (b *Base) SetData(m map[string]interface{}) error {
+	if b.customCheck != nil {
+		checked, err := b.customCheck(m)
+		if err != nil {
+			return err
+		}
+		m = checked
+	}
+
 	b.obj = map[string]interface{}{}
 
 	// Set the data
@@ -487,7 +463,7 @@ func (b *Base) SetData(m map[string]interface{}) error {
 		d, ok := m[key]
 		if !ok || d == nil {
 			if handler.IsRequired() {
-				return fmt.Errorf("The property %q is required", key)
+				return newValidationError(b, key, fmt.Errorf("The property %q is required", key))
 			}
 
 			continue
@@ -495,7 +471,7 @@ func (b *Base) SetData(m map[string]interface{}) error {
 
 		err := handler.Set(d)
 		if err != nil {
-			return err
+			return newValidationError(b, key, err)
 		}
 
 		// Save the data object
@@ -505,17 +481,23 @@ func (b *Base) SetData(m map[string]interface{}) error {
 	// Validate the data
 	if b.validator != nil {
 		if err := b.validator(); err != nil {
-			return err
+			return newValidationError(b, "", err)
 		}
 	}
 
-	// Save the custom data
+	// Save the custom data in the IPLD data model
+	custom := map[string]interface{}{}
 	for key, value := range m {
 		_, exist := b.data[key]
 		if !exist {
-			b.custom[key] = value
+			norm, err := NormalizeCustom(value)
+			if err != nil {
+				return newValidationError(b, key, err)
+			}
+			custom[key] = norm
 		}
 	}
+	b.custom = custom
 
 	return nil
 }
@@ -551,8 +533,9 @@ func (b *Base) Encode() (map[string]interface{}, error) {
 		m[key] = value
 	}
 
-	// CBOR-ise the data
-	rawData, err := cbor.DumpObject(m)
+	// CBOR-ise the data in the canonical form, so that the same data always
+	// has the same CID
+	rawData, err := EncodeCanonical(m)
 	if err != nil {
 		return nil, err
 	}
@@ -586,7 +569,7 @@ func (b *Base) Decode(m map[string]interface{}) error {
 		d, ok := m[key]
 		if !ok || d == nil {
 			if handler.IsRequired() {
-				return fmt.Errorf("The property %q is required", key)
+				return newValidationError(b, key, fmt.Errorf("The property %q is required", key))
 			}
 
 			continue
@@ -594,7 +577,7 @@ func (b *Base) Decode(m map[string]interface{}) error {
 
 		dec, err := handler.Decode(d)
 		if err != nil {
-			return err
+			return newValidationError(b, key, err)
 		}
 		b.obj[key] = dec
 
@@ -604,12 +587,20 @@ func (b *Base) Decode(m map[string]interface{}) error {
 	// Validate the data
 	if b.validator != nil {
 		if err := b.validator(); err != nil {
-			return err
+			return newValidationError(b, "", err)
 		}
 	}
 
-	// Save the custom data
-	b.custom = m
+	// Save the custom data in the IPLD data model
+	custom := map[string]interface{}{}
+	for key, value := range m {
+		norm, err := NormalizeCustom(value)
+		if err != nil {
+			return newValidationError(b, key, err)
+		}
+		custom[key] = norm
+	}
+	b.custom = custom
 
 	return nil
 }
@@ -668,8 +659,11 @@ func (b *Base) Resolve(path []string) (interface{}, []string, error) {
 
 	// Handle custom parameters
 	var obj interface{} = b.custom
-	for _, key := range path {
+	for n, key := range path {
 		switch value := obj.(type) {
+		case cid.Cid:
+			// Stop at the link boundary
+			return &node.Link{Cid: value}, path[n:], nil
 		case map[string]interface{}:
 			v, ok := value[key]
 			if !ok {
@@ -677,12 +671,12 @@ func (b *Base) Resolve(path []string) (interface{}, []string, error) {
 			}
 			obj = v
 		case []interface{}:
-			i, err := strconv.ParseInt(key, 10, 64)
+			i, err := strconv.ParseUint(key, 10, 64)
 			if err != nil {
 				return nil, nil, fmt.Errorf("no such link")
 			}
 
-			if i >= int64(len(value)) {
+			if i >= uint64(len(value)) {
 				return nil, nil, fmt.Errorf("index %d does not exist", i)
 			}
 
@@ -692,6 +686,10 @@ func (b *Base) Resolve(path []string) (interface{}, []string, error) {
 		}
 	}
 
+	if c, ok := obj.(cid.Cid); ok {
+		return &node.Link{Cid: c}, nil, nil
+	}
+
 	return obj, nil, nil
 }
 
@@ -0,0 +1,162 @@
+package block
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/ipfs/go-cid"
+)
+
+// ==================================================
+// Canonical DAG-CBOR
+// ==================================================
+
+// ErrNotCanonical is the error of a block which is not in the canonical
+// DAG-CBOR encoding
+var ErrNotCanonical = errors.New("Block is not in the canonical DAG-CBOR encoding")
+
+// cborTagLink is the CBOR tag of IPLD links
+const cborTagLink = 42
+
+// EncodeCanonical encodes the data in the IPLD data model with the strict
+// DAG-CBOR rules: map keys are sorted by length and then bytewise, integers
+// and lengths are in the shortest form, lengths are definite, floats are
+// always 64-bit and links are CIDs with tag 42
+func EncodeCanonical(value interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := encodeCanonical(buf, value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeCanonical(buf *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buf.WriteByte(cborSimple<<5 | 22)
+	case bool:
+		if v {
+			buf.WriteByte(cborSimple<<5 | 21)
+		} else {
+			buf.WriteByte(cborSimple<<5 | 20)
+		}
+	case uint64:
+		writeHeader(buf, cborUint, v)
+	case int64:
+		if v >= 0 {
+			writeHeader(buf, cborUint, uint64(v))
+		} else {
+			writeHeader(buf, cborNegInt, uint64(-(v + 1)))
+		}
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%v is not a valid float", v)
+		}
+		buf.WriteByte(cborSimple<<5 | 27)
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, math.Float64bits(v))
+		buf.Write(b)
+	case string:
+		if !utf8.ValidString(v) {
+			return fmt.Errorf("%q is not a valid UTF-8 string", v)
+		}
+		writeHeader(buf, cborText, uint64(len(v)))
+		buf.WriteString(v)
+	case []byte:
+		writeHeader(buf, cborBytes, uint64(len(v)))
+		buf.Write(v)
+	case cid.Cid:
+		if !v.Defined() {
+			return fmt.Errorf("Undefined CID is not a valid link")
+		}
+		// The multibase prefix of the binary form
+		raw := append([]byte{0}, v.Bytes()...)
+		writeHeader(buf, cborTag, cborTagLink)
+		writeHeader(buf, cborBytes, uint64(len(raw)))
+		buf.Write(raw)
+	case []interface{}:
+		writeHeader(buf, cborArray, uint64(len(v)))
+		for _, elem := range v {
+			if err := encodeCanonical(buf, elem); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) < len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+
+		writeHeader(buf, cborMap, uint64(len(v)))
+		for _, key := range keys {
+			if !utf8.ValidString(key) {
+				return fmt.Errorf("%q is not a valid UTF-8 string", key)
+			}
+			writeHeader(buf, cborText, uint64(len(key)))
+			buf.WriteString(key)
+			if err := encodeCanonical(buf, v[key]); err != nil {
+				return fmt.Errorf("%s: %s", key, err)
+			}
+		}
+	default:
+		// Other Go types are converted to the IPLD data model first
+		norm, err := NormalizeCustom(value)
+		if err != nil {
+			return err
+		}
+		return encodeCanonical(buf, norm)
+	}
+
+	return nil
+}
+
+// writeHeader writes the major type and the argument in the shortest form
+func writeHeader(buf *bytes.Buffer, major byte, arg uint64) {
+	switch {
+	case arg < 24:
+		buf.WriteByte(major<<5 | byte(arg))
+	case arg <= math.MaxUint8:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(arg))
+	case arg <= math.MaxUint16:
+		buf.WriteByte(major<<5 | 25)
+		b := make([]byte, 2)
+		binary.BigEndian.PutUint16(b, uint16(arg))
+		buf.Write(b)
+	case arg <= math.MaxUint32:
+		buf.WriteByte(major<<5 | 26)
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(arg))
+		buf.Write(b)
+	default:
+		buf.WriteByte(major<<5 | 27)
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, arg)
+		buf.Write(b)
+	}
+}
+
+// checkCanonical checks whether the raw data is the canonical encoding of
+// its decoded CBOR data
+func checkCanonical(raw []byte, m map[string]interface{}) error {
+	enc, err := EncodeCanonical(m)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotCanonical, err)
+	}
+
+	if !bytes.Equal(enc, raw) {
+		return ErrNotCanonical
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package block
+
+import (
+	"errors"
+	"testing"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+)
+
+func TestCheckCanonical(t *testing.T) {
+	cases := []struct {
+		name      string
+		raw       []byte
+		canonical bool
+	}{
+		{
+			"canonical",
+			[]byte{0xa2, 0x61, 0x62, 0x01, 0x62, 0x61, 0x61, 0x01},
+			true,
+		},
+		{
+			"float64",
+			[]byte{0xa1, 0x61, 0x66, 0xfb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0},
+			true,
+		},
+		{
+			// Keys are sorted by length first
+			"keys sorted bytewise",
+			[]byte{0xa2, 0x62, 0x61, 0x61, 0x01, 0x61, 0x62, 0x01},
+			false,
+		},
+		{
+			"unsorted keys",
+			[]byte{0xa2, 0x61, 0x62, 0x01, 0x61, 0x61, 0x01},
+			false,
+		},
+		{
+			"non-minimal integer",
+			[]byte{0xa1, 0x61, 0x61, 0x18, 0x01},
+			false,
+		},
+		{
+			"non-minimal 64-bit integer",
+			[]byte{0xa1, 0x61, 0x61, 0x1b, 0, 0, 0, 0, 0, 0, 0x01, 0x00},
+			false,
+		},
+		{
+			"non-minimal string length",
+			[]byte{0xa1, 0x78, 0x01, 0x61, 0x01},
+			false,
+		},
+		{
+			"non-minimal map length",
+			[]byte{0xb8, 0x01, 0x61, 0x61, 0x01},
+			false,
+		},
+		{
+			"non-minimal array length",
+			[]byte{0xa1, 0x61, 0x61, 0x98, 0x01, 0x01},
+			false,
+		},
+		{
+			"32-bit float",
+			[]byte{0xa1, 0x61, 0x66, 0xfa, 0x3f, 0xc0, 0, 0},
+			false,
+		},
+		{
+			"16-bit float",
+			[]byte{0xa1, 0x61, 0x66, 0xf9, 0x3e, 0x00},
+			false,
+		},
+	}
+
+	for _, c := range cases {
+		m := map[string]interface{}{}
+		if err := cbor.DecodeInto(c.raw, &m); err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+
+		err := checkCanonical(c.raw, m)
+		if c.canonical && err != nil {
+			t.Errorf("%s: %s", c.name, err)
+		}
+		if !c.canonical && !errors.Is(err, ErrNotCanonical) {
+			t.Errorf("%s: ErrNotCanonical is expected but %v is found", c.name, err)
+		}
+	}
+}
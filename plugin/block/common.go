@@ -9,6 +9,7 @@ const (
 	CodecStakeholders = 0x0266
 	CodecContent      = 0x0267
 	CodecEntity       = 0x0268
+	CodecAttestation  = 0x0269
 
 	// Internal Codec
 	CodecRight       = 0x02BD
@@ -19,7 +20,7 @@ const (
 // IsIscnObject checks the codec whether belongs an ISCN object
 func IsIscnObject(codec uint64) bool {
 	switch codec {
-	case CodecISCN, CodecRights, CodecStakeholders, CodecContent, CodecEntity:
+	case CodecISCN, CodecRights, CodecStakeholders, CodecContent, CodecEntity, CodecAttestation:
 		return true
 	default:
 		return false
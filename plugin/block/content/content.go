@@ -1,8 +1,12 @@
 package content
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/likecoin/iscn-ipld/plugin/block"
 	"github.com/likecoin/iscn-ipld/plugin/block/data"
+	"github.com/likecoin/iscn-ipld/plugin/fingerprint"
 )
 
 const (
@@ -10,13 +14,27 @@ const (
 	SchemaName = "content"
 )
 
-// Register registers the schema of content block
+// Register registers the schema of content block to the default registry
 func Register() {
-	block.RegisterIscnObjectFactory(
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers the schema of content block to the registry
+func RegisterTo(r *block.Registry) {
+	r.Register(
 		block.CodecContent,
 		SchemaName,
 		newSchemaV1,
+		newSchemaV2,
+		newSchemaV3,
+		newSchemaV4,
+		newSchemaV5,
 	)
+
+	r.RegisterMigration(block.CodecContent, 1, migrateV1ToV2)
+	r.RegisterMigration(block.CodecContent, 2, migrateV2ToV3)
+	r.RegisterMigration(block.CodecContent, 3, migrateV3ToV4)
+	r.RegisterMigration(block.CodecContent, 4, migrateV4ToV5)
 }
 
 // ==================================================
@@ -93,3 +111,248 @@ func newSchemaV1() (block.Codec, error) {
 func (o *schemaV1) Validate() error {
 	return data.ValidateParent(o.version, o.parent)
 }
+
+// ==================================================
+// schemaV2
+// ==================================================
+
+// schemaV2 represents a content V2, the fingerprint can also be an IPFS CID
+// or a multihash
+type schemaV2 struct {
+	*base
+
+	version *data.Number
+	parent  *data.Cid
+}
+
+var _ block.IscnObject = (*schemaV2)(nil)
+
+func newSchemaV2() (block.Codec, error) {
+	version := data.NewNumber("version", true, data.Uint64T)
+	parent := data.NewCid("parent", false, block.CodecContent)
+
+	schema := []data.Data{
+		data.NewString("type", true),
+		version,
+		parent,
+		data.NewURL("source", false),
+		data.NewString("edition", false),
+		NewFingerprint("fingerprint", true),
+		data.NewString("title", true),
+		data.NewString("description", false),
+		data.NewDataArray("tags", false, data.NewString("_", false)),
+	}
+
+	contentBase, err := newBase(2, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV2{
+		base:    contentBase,
+		version: version,
+		parent:  parent,
+	}
+	contentBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// Validate the data
+func (o *schemaV2) Validate() error {
+	return data.ValidateParent(o.version, o.parent)
+}
+
+// ==================================================
+// schemaV3
+// ==================================================
+
+// schemaV3 represents a content V3, the parent is encoded as a tag 42 link
+type schemaV3 struct {
+	*base
+
+	version *data.Number
+	parent  *data.Cid
+}
+
+var _ block.IscnObject = (*schemaV3)(nil)
+
+func newSchemaV3() (block.Codec, error) {
+	version := data.NewNumber("version", true, data.Uint64T)
+	parent := data.NewLink("parent", false, block.CodecContent)
+
+	schema := []data.Data{
+		data.NewString("type", true),
+		version,
+		parent,
+		data.NewURL("source", false),
+		data.NewString("edition", false),
+		NewFingerprint("fingerprint", true),
+		data.NewString("title", true),
+		data.NewString("description", false),
+		data.NewDataArray("tags", false, data.NewString("_", false)),
+	}
+
+	contentBase, err := newBase(3, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV3{
+		base:    contentBase,
+		version: version,
+		parent:  parent,
+	}
+	contentBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// Validate the data
+func (o *schemaV3) Validate() error {
+	return data.ValidateParent(o.version, o.parent)
+}
+
+// ==================================================
+// schemaV4
+// ==================================================
+
+// schemaV4 represents a content V4, the version is encoded as a native CBOR
+// integer
+type schemaV4 struct {
+	*base
+
+	version *data.Number
+	parent  *data.Cid
+}
+
+var _ block.IscnObject = (*schemaV4)(nil)
+
+func newSchemaV4() (block.Codec, error) {
+	version := data.NewNativeNumber("version", true, data.Uint64T)
+	parent := data.NewLink("parent", false, block.CodecContent)
+
+	schema := []data.Data{
+		data.NewString("type", true),
+		version,
+		parent,
+		data.NewURL("source", false),
+		data.NewString("edition", false),
+		NewFingerprint("fingerprint", true),
+		data.NewString("title", true),
+		data.NewString("description", false),
+		data.NewDataArray("tags", false, data.NewString("_", false)),
+	}
+
+	contentBase, err := newBase(4, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV4{
+		base:    contentBase,
+		version: version,
+		parent:  parent,
+	}
+	contentBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// Validate the data
+func (o *schemaV4) Validate() error {
+	return data.ValidateParent(o.version, o.parent)
+}
+
+// ==================================================
+// schemaV5
+// ==================================================
+
+// schemaV5 represents a content V5, the title and the description are
+// localized strings
+type schemaV5 struct {
+	*base
+
+	version *data.Number
+	parent  *data.Cid
+}
+
+var _ block.IscnObject = (*schemaV5)(nil)
+
+func newSchemaV5() (block.Codec, error) {
+	version := data.NewNativeNumber("version", true, data.Uint64T)
+	parent := data.NewLink("parent", false, block.CodecContent)
+
+	schema := []data.Data{
+		data.NewString("type", true),
+		version,
+		parent,
+		data.NewURL("source", false),
+		data.NewString("edition", false),
+		NewFingerprint("fingerprint", true),
+		data.NewLocalizedString("title", true, data.UndeterminedLanguage),
+		data.NewLocalizedString("description", false, data.UndeterminedLanguage),
+		data.NewDataArray("tags", false, data.NewString("_", false)),
+	}
+
+	contentBase, err := newBase(5, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV5{
+		base:    contentBase,
+		version: version,
+		parent:  parent,
+	}
+	contentBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// Validate the data
+func (o *schemaV5) Validate() error {
+	return data.ValidateParent(o.version, o.parent)
+}
+
+// ==================================================
+// Migrations
+// ==================================================
+
+// migrateV1ToV2 migrates a content V1 to V2, "hash://" URIs are normalized,
+// those of the hash algorithms not registered are carried over unverified
+// and reported as lossy
+func migrateV1ToV2(m map[string]interface{}) ([]string, error) {
+	value, ok := m["fingerprint"].(string)
+	if !ok {
+		return nil, fmt.Errorf("Fingerprint: 'string' is expected but '%T' is found", m["fingerprint"])
+	}
+
+	f, err := fingerprint.Parse(value)
+	if errors.Is(err, fingerprint.ErrUnknownAlgorithm) {
+		return []string{fmt.Sprintf("fingerprint %q is not verified (%s)", value, err)}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	m["fingerprint"] = f.String()
+	return nil, nil
+}
+
+// migrateV2ToV3 migrates a content V2 to V3, the parent is converted to a
+// link
+func migrateV2ToV3(m map[string]interface{}) ([]string, error) {
+	return nil, block.BytesToLinks(m, "parent")
+}
+
+// migrateV3ToV4 migrates a content V3 to V4, the varint version is decoded
+// by the native number handler as is
+func migrateV3ToV4(m map[string]interface{}) ([]string, error) {
+	return nil, nil
+}
+
+// migrateV4ToV5 migrates a content V4 to V5, the title and the description
+// are carried over in the undetermined language
+func migrateV4ToV5(m map[string]interface{}) ([]string, error) {
+	return nil, block.LocalizeStrings(m, data.UndeterminedLanguage, "title", "description")
+}
@@ -0,0 +1,177 @@
+package content
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	mbase "github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// The CIDs of the same content in each version are pinned, as they change
+// when the encoding of the CIDs or the numbers changes: v1 and v2 encode both
+// as byte strings, v3 encodes the CIDs as tag 42 links and v4 and v5 also
+// encode the numbers as native CBOR integers
+func TestContentCids(t *testing.T) {
+	r := block.NewRegistry()
+	RegisterTo(r)
+
+	h, err := mh.Sum([]byte("parent"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := map[string]interface{}{
+		"version":     uint64(2),
+		"parent":      cid.NewCidV1(block.CodecContent, h),
+		"type":        "article",
+		"source":      "https://example.com/article",
+		"fingerprint": "hash://sha256/9564b85669d5e96ac969dd0161b8475bbced9e5999c6ec598da718a3045d6f2e",
+		"title":       "Title",
+		"tags":        []interface{}{"a", "b"},
+	}
+
+	cases := []struct {
+		version uint64
+		cid     string
+	}{
+		{1, "bahtqierafbx6vom5vjd6d3o2p6asmwy4qhfnvhdqztzyf6unpmajs6nxryla"},
+		{2, "bahtqieractdqdy5tejpsaa6j2rnwzkbpxjgeiactoboekakszuq6vucys2pq"},
+		{3, "bahtqieraj4kyancitr3sdkts23ui6fian5nslrbe5n6jyj2iha3y6buaegia"},
+		{4, "bahtqierabsrequpnaa52unzkttnp2upg5jxfyfyo7udrvmzdwrxu5ubmqegq"},
+		{5, "bahtqiera57ausrqcy6s7dtrbdqapiazzzik26l7kd2hb4f4fjaq6w6yrm3va"},
+	}
+
+	for _, c := range cases {
+		obj, err := r.Encode(block.CodecContent, c.version, m)
+		if err != nil {
+			t.Errorf("v%d: %s", c.version, err)
+			continue
+		}
+
+		if obj.Cid().String() != c.cid {
+			t.Errorf("v%d: CID %s is expected but %s is found", c.version, c.cid, obj.Cid())
+		}
+
+		dec, err := r.Decode(obj.RawData(), obj.Cid())
+		if err != nil {
+			t.Errorf("v%d: %s", c.version, err)
+			continue
+		}
+
+		if !dec.Cid().Equals(obj.Cid()) {
+			t.Errorf("v%d: decoded CID %s is expected but %s is found", c.version, obj.Cid(), dec.Cid())
+		}
+	}
+}
+
+func TestMigrateUnknownAlgorithm(t *testing.T) {
+	r := block.NewRegistry()
+	RegisterTo(r)
+
+	value := "hash://unknown-hash/abcd"
+	obj, err := r.Encode(block.CodecContent, 1, map[string]interface{}{
+		"version":     uint64(1),
+		"type":        "article",
+		"fingerprint": value,
+		"title":       "Title",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, report, err := r.Migrate(obj, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Losses) != 1 {
+		t.Errorf("1 loss is expected but %q is found", report.Losses)
+	}
+
+	if f, err := res.GetString("fingerprint"); err != nil || f != value {
+		t.Errorf("Fingerprint %q is expected but %q (%v) is found", value, f, err)
+	}
+
+	dec, err := r.Decode(res.RawData(), res.Cid())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !dec.Cid().Equals(res.Cid()) {
+		t.Errorf("Decoded CID %s is expected but %s is found", res.Cid(), dec.Cid())
+	}
+
+	// New blocks are still verified
+	_, err = r.Encode(block.CodecContent, 5, map[string]interface{}{
+		"version":     uint64(1),
+		"type":        "article",
+		"fingerprint": value,
+		"title":       "Title",
+	})
+	if err == nil {
+		t.Errorf("Fingerprint %q of an unknown algorithm is accepted", value)
+	}
+}
+
+// A fingerprint stored in a form other than the normalized one, e.g. a CIDv1
+// in base58, is decoded as is so that the block still matches its CID
+func TestDecodeFingerprintAsIs(t *testing.T) {
+	r := block.NewRegistry()
+	RegisterTo(r)
+
+	h, err := mh.Sum([]byte("hello"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := cid.NewCidV1(cid.Raw, h).StringOfBase(mbase.Base58BTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, version := range []uint64{2, 5} {
+		obj, err := r.New(block.CodecContent, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = obj.SetData(map[string]interface{}{
+			"version":     uint64(1),
+			"type":        "article",
+			"fingerprint": "hash://sha256/2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			"title":       "Title",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		m, err := obj.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		m["fingerprint"] = value
+
+		raw, err := block.EncodeCanonical(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sum, err := mh.Sum(raw, mh.SHA2_256, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dec, err := r.Decode(raw, cid.NewCidV1(block.CodecContent, sum))
+		if err != nil {
+			t.Errorf("v%d: %s", version, err)
+			continue
+		}
+
+		if f, err := dec.GetString("fingerprint"); err != nil || f != value {
+			t.Errorf("v%d: fingerprint %q is expected but %q (%v) is found", version, value, f, err)
+		}
+	}
+}
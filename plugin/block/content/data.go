@@ -0,0 +1,111 @@
+package content
+
+import (
+	"errors"
+
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+	"github.com/likecoin/iscn-ipld/plugin/fingerprint"
+)
+
+// ==================================================
+// Fingerprint
+// ==================================================
+
+// Fingerprint is a data handler for the fingerprint of the content, which
+// can be a "hash://" URI, an IPFS CID or a multibase encoded multihash
+type Fingerprint struct {
+	*data.Base
+
+	value       *data.String
+	fingerprint *fingerprint.Fingerprint
+}
+
+var _ data.Data = (*Fingerprint)(nil)
+
+// NewFingerprint creates a fingerprint data handler
+func NewFingerprint(key string, isRequired bool) *Fingerprint {
+	return &Fingerprint{
+		Base:  data.NewBase(key, isRequired),
+		value: data.NewString("", false),
+	}
+}
+
+// Prototype creates a prototype Fingerprint
+func (d *Fingerprint) Prototype() data.Data {
+	return &Fingerprint{
+		Base:  d.Base.Prototype(),
+		value: data.NewString("", false),
+	}
+}
+
+// Get returns the fingerprint, which is nil if it is a decoded "hash://" URI
+// of an algorithm not registered
+func (d *Fingerprint) Get() *fingerprint.Fingerprint {
+	return d.fingerprint
+}
+
+// Set the value of Fingerprint
+func (d *Fingerprint) Set(obj interface{}) error {
+	if err := d.value.Set(obj); err != nil {
+		return err
+	}
+
+	f, err := fingerprint.ParseAny(d.value.Get())
+	if err != nil {
+		return err
+	}
+
+	// Store the normalized form so that the same fingerprint always produces
+	// the same block
+	if err := d.value.Set(f.String()); err != nil {
+		return err
+	}
+
+	d.fingerprint = f
+	d.Base.MarkDefined()
+	return nil
+}
+
+// Encode Fingerprint
+func (d *Fingerprint) Encode() (interface{}, error) {
+	return d.value.Encode()
+}
+
+// Decode Fingerprint, the stored value is kept as is so that the block is
+// encoded to the same bytes, and the "hash://" URIs of the algorithms not
+// registered are accepted, so that the stored blocks do not depend on the
+// algorithms registered by the process decoding them
+func (d *Fingerprint) Decode(obj interface{}) (interface{}, error) {
+	if err := d.value.Set(obj); err != nil {
+		return nil, err
+	}
+
+	f, err := fingerprint.ParseAny(d.value.Get())
+	if errors.Is(err, fingerprint.ErrUnknownAlgorithm) {
+		f = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	d.fingerprint = f
+	d.Base.MarkDefined()
+	return d.value.Get(), nil
+}
+
+// ToJSON prepares the data for MarshalJSON
+func (d *Fingerprint) ToJSON() (interface{}, error) {
+	return d.value.ToJSON()
+}
+
+// Resolve resolves the value
+func (d *Fingerprint) Resolve(path []string) (interface{}, []string, error) {
+	return d.value.Resolve(path)
+}
+
+// KindFingerprint is the kind of content fingerprint handler
+const KindFingerprint data.Kind = "fingerprint"
+
+// Describe returns the descriptor of Fingerprint
+func (d *Fingerprint) Describe() *data.Descriptor {
+	return data.NewDescriptor(d.GetKey(), KindFingerprint, d.IsRequired())
+}
@@ -0,0 +1,194 @@
+package block
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+)
+
+// ==================================================
+// CustomPolicy
+// ==================================================
+
+// CustomMode is the mode of handling custom properties, which are the
+// properties not defined in the schema
+type CustomMode int
+
+const (
+	// Permissive accepts any custom property
+	Permissive CustomMode = iota
+
+	// Strict rejects any custom property not of a registered extension
+	Strict
+
+	// Namespaced accepts the custom properties with a registered prefix, of
+	// a registered extension or named by a hierarchical URI with a host
+	Namespaced
+)
+
+// CustomPolicy is the policy of custom properties of ISCN objects
+type CustomPolicy struct {
+	Mode CustomMode
+
+	// Prefixes are the allowed prefixes of the custom properties in
+	// Namespaced mode, e.g. "x-"
+	Prefixes []string
+}
+
+// PermissivePolicy accepts any custom property
+func PermissivePolicy() CustomPolicy {
+	return CustomPolicy{Mode: Permissive}
+}
+
+// StrictPolicy rejects any custom property not of a registered extension
+func StrictPolicy() CustomPolicy {
+	return CustomPolicy{Mode: Strict}
+}
+
+// NamespacedPolicy accepts the custom properties with one of the prefixes or
+// named by a hierarchical URI with a host, e.g. "https://schema.org/name"
+func NamespacedPolicy(prefixes ...string) CustomPolicy {
+	return CustomPolicy{
+		Mode:     Namespaced,
+		Prefixes: append([]string{}, prefixes...),
+	}
+}
+
+// Allows checks whether the custom property is allowed
+func (p CustomPolicy) Allows(key string) bool {
+	switch p.Mode {
+	case Permissive:
+		return true
+	case Namespaced:
+		for _, prefix := range p.Prefixes {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+
+		// Only hierarchical URIs, e.g. "https://schema.org/name", as any
+		// "a:b" key would be an opaque URI
+		u, err := url.Parse(key)
+		return err == nil && u.IsAbs() && u.Host != ""
+	}
+
+	return false
+}
+
+// reject returns the error of a custom property not allowed by the policy
+func (p CustomPolicy) reject(key string) error {
+	if p.Mode == Namespaced {
+		return fmt.Errorf("Custom property %q is not namespaced", key)
+	}
+	return fmt.Errorf("Unknown property %q", key)
+}
+
+// SetCustomPolicy sets the policy of custom properties of all codecs without
+// their own policies
+func (r *Registry) SetCustomPolicy(policy CustomPolicy) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.customPolicy = policy
+}
+
+// SetCodecCustomPolicy sets the policy of custom properties of the codec,
+// which overrides the policy of the registry
+func (r *Registry) SetCodecCustomPolicy(codec uint64, policy CustomPolicy) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.codecCustomPolicies[codec] = policy
+}
+
+// CustomPolicy returns the policy of custom properties of the codec
+func (r *Registry) CustomPolicy(codec uint64) CustomPolicy {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if policy, ok := r.codecCustomPolicies[codec]; ok {
+		return policy
+	}
+	return r.customPolicy
+}
+
+// ==================================================
+// Custom properties
+// ==================================================
+
+// walkCustomKeys calls fn with the sorted custom properties of the encoded or
+// decoded data and of each nested object, path is the dotted path of the
+// object and m is its data
+func walkCustomKeys(
+	m map[string]interface{},
+	fields []*data.Descriptor,
+	path string,
+	fn func(path string, m map[string]interface{}, keys []string) error,
+) error {
+	byKey := map[string]*data.Descriptor{}
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	keys := []string{}
+	for key, value := range m {
+		field, ok := byKey[key]
+		if !ok {
+			if key != data.ContextKey {
+				keys = append(keys, key)
+			}
+			continue
+		}
+
+		if err := walkNestedCustomKeys(value, field, join(path, key), fn); err != nil {
+			return err
+		}
+	}
+	sort.Strings(keys)
+
+	return fn(path, m, keys)
+}
+
+func walkNestedCustomKeys(
+	value interface{},
+	field *data.Descriptor,
+	path string,
+	fn func(path string, m map[string]interface{}, keys []string) error,
+) error {
+	switch field.Kind {
+	case data.KindObject:
+		if m, ok := value.(map[string]interface{}); ok {
+			return walkCustomKeys(m, field.Fields, path, fn)
+		}
+	case data.KindArray:
+		if array, ok := value.([]interface{}); ok && field.Elem != nil {
+			for i, elem := range array {
+				err := walkNestedCustomKeys(elem, field.Elem, join(path, fmt.Sprint(i)), fn)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	case data.KindMap:
+		if m, ok := value.(map[string]interface{}); ok && field.Elem != nil {
+			for _, key := range sortedKeys(m) {
+				err := walkNestedCustomKeys(m[key], field.Elem, join(path, key), fn)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,62 @@
+package block_test
+
+import (
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+)
+
+func TestNamespacedPolicy(t *testing.T) {
+	policy := block.NamespacedPolicy("x-")
+
+	cases := []struct {
+		key     string
+		allowed bool
+	}{
+		{"x-license", true},
+		{"https://schema.org/name", true},
+		{"http://example.com", true},
+		{"license", false},
+		{"x:foo", false},
+		{"urn:isbn:0451450523", false},
+		{"mailto:someone@example.com", false},
+		{"file:///etc/passwd", false},
+		{"//example.com/name", false},
+	}
+
+	for _, c := range cases {
+		if allowed := policy.Allows(c.key); allowed != c.allowed {
+			t.Errorf("Allows(%q) = %v, %v is expected", c.key, allowed, c.allowed)
+		}
+	}
+}
+
+func TestSetDataPolicy(t *testing.T) {
+	r := block.NewRegistry()
+	content.RegisterTo(r)
+	r.SetCustomPolicy(block.NamespacedPolicy("x-"))
+
+	obj, err := r.New(block.CodecContent, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := newContent()
+	m["x:foo"] = "bar"
+	if err := obj.SetData(m); err == nil {
+		t.Errorf("SetData: custom property %q is accepted", "x:foo")
+	}
+
+	m = newContent()
+	m["x-foo"] = "bar"
+	if err := obj.SetData(m); err != nil {
+		t.Errorf("SetData: %s", err)
+	}
+
+	m = newContent()
+	m["x:foo"] = "bar"
+	if _, err := r.Encode(block.CodecContent, 5, m); err == nil {
+		t.Errorf("Encode: custom property %q is accepted", "x:foo")
+	}
+}
@@ -38,11 +38,22 @@ func (d *Array) Prototype() Data {
 	}
 }
 
+// GetPrototype returns the prototype of the elements
+func (d *Array) GetPrototype() Data {
+	return d.prototype
+}
+
+// Elems returns the data handlers of the elements
+func (d *Array) Elems() []Data {
+	return d.array
+}
+
 // Set the value of data handler array
 func (d *Array) Set(obj interface{}) error {
-	switch reflect.TypeOf(obj).Kind() {
+	// reflect.ValueOf(nil) is an invalid value instead of panicking
+	s := reflect.ValueOf(obj)
+	switch s.Kind() {
 	case reflect.Slice:
-		s := reflect.ValueOf(obj)
 		for i := 0; i < s.Len(); i++ {
 			elem := d.prototype.Prototype()
 			if err := elem.Set(s.Index(i).Interface()); err != nil {
@@ -74,10 +85,10 @@ func (d *Array) Encode() (interface{}, error) {
 
 // Decode Array
 func (d *Array) Decode(obj interface{}) (interface{}, error) {
-	switch reflect.TypeOf(obj).Kind() {
+	s := reflect.ValueOf(obj)
+	switch s.Kind() {
 	case reflect.Slice:
 		res := []interface{}{}
-		s := reflect.ValueOf(obj)
 		for i := 0; i < s.Len(); i++ {
 			elem := d.prototype.Prototype()
 			dec, err := elem.Decode(s.Index(i).Interface())
@@ -136,3 +147,10 @@ func (d *Array) Resolve(path []string) (interface{}, []string, error) {
 
 	return d.array[index].Resolve(rest)
 }
+
+// Describe returns the descriptor of Array
+func (d *Array) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindArray, d.IsRequired())
+	desc.Elem = Describe(d.prototype)
+	return desc
+}
@@ -0,0 +1,81 @@
+package data
+
+import (
+	"fmt"
+)
+
+// ==================================================
+// Bool
+// ==================================================
+
+// Bool is a data handler for the boolean
+type Bool struct {
+	*Base
+
+	value bool
+}
+
+var _ Data = (*Bool)(nil)
+
+// NewBool creates a boolean data handler
+func NewBool(key string, isRequired bool) *Bool {
+	return &Bool{
+		Base: NewBase(key, isRequired),
+	}
+}
+
+// Prototype creates a prototype Bool
+func (d *Bool) Prototype() Data {
+	return &Bool{
+		Base: d.Base.Prototype(),
+	}
+}
+
+// Get returns the boolean value
+func (d *Bool) Get() bool {
+	return d.value
+}
+
+// Set the value of Bool
+func (d *Bool) Set(obj interface{}) error {
+	if value, ok := obj.(bool); ok {
+		d.value = value
+		d.Base.MarkDefined()
+		return nil
+	}
+
+	return fmt.Errorf("Bool: 'bool' is expected but '%T' is found", obj)
+}
+
+// Encode Bool
+func (d *Bool) Encode() (interface{}, error) {
+	return d.value, nil
+}
+
+// Decode Bool
+func (d *Bool) Decode(obj interface{}) (interface{}, error) {
+	if err := d.Set(obj); err != nil {
+		return nil, err
+	}
+
+	return d.value, nil
+}
+
+// ToJSON prepares the data for MarshalJSON
+func (d *Bool) ToJSON() (interface{}, error) {
+	return d.value, nil
+}
+
+// Resolve resolves the value
+func (d *Bool) Resolve(path []string) (interface{}, []string, error) {
+	if len(path) != 0 {
+		return nil, nil, fmt.Errorf("Unexpected path elements past %s", path[0])
+	}
+
+	return d.value, nil, nil
+}
+
+// Describe returns the descriptor of Bool
+func (d *Bool) Describe() *Descriptor {
+	return NewDescriptor(d.GetKey(), KindBool, d.IsRequired())
+}
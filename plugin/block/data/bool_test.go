@@ -0,0 +1,38 @@
+package data
+
+import (
+	"testing"
+)
+
+func TestBool(t *testing.T) {
+	d := NewBool("b", true)
+	if err := d.Set("true"); err == nil {
+		t.Errorf("Set(%q): error is expected", "true")
+	}
+	if d.IsDefined() {
+		t.Errorf("Bool is defined after a failed Set")
+	}
+
+	for _, b := range []bool{true, false} {
+		value, err := d.Decode(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if value != b || d.Get() != b {
+			t.Errorf("Decode(%v): %v is found", b, value)
+		}
+
+		enc, err := d.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if enc != b {
+			t.Errorf("Encode: %v is expected but %v is found", b, enc)
+		}
+	}
+
+	if _, err := d.Decode(uint64(1)); err == nil {
+		t.Errorf("Decode(1): error is expected")
+	}
+}
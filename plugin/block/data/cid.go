@@ -12,12 +12,14 @@ import (
 // Cid
 // ==================================================
 
-// Cid is a data handler for IPFS CID
+// Cid is a data handler for IPFS CID, which is encoded as a byte string or as
+// a tag 42 link of IPLD
 type Cid struct {
 	*Base
 
-	codec uint64
-	c     []byte
+	codec  uint64
+	isLink bool
+	c      []byte
 }
 
 var _ Data = (*Cid)(nil)
@@ -30,14 +32,34 @@ func NewCid(key string, isRequired bool, codec uint64) *Cid {
 	}
 }
 
+// NewLink creates a IPFS CID data handler encoding the CID as a tag 42 link
+func NewLink(key string, isRequired bool, codec uint64) *Cid {
+	return &Cid{
+		Base:   NewBase(key, isRequired),
+		codec:  codec,
+		isLink: true,
+	}
+}
+
 // Prototype creates a prototype Cid
 func (d *Cid) Prototype() Data {
 	return &Cid{
-		Base:  d.Base.Prototype(),
-		codec: d.codec,
+		Base:   d.Base.Prototype(),
+		codec:  d.codec,
+		isLink: d.isLink,
 	}
 }
 
+// IsLink checks whether the CID is encoded as a tag 42 link
+func (d *Cid) IsLink() bool {
+	return d.isLink
+}
+
+// GetCodec returns the codec of the linked block, 0 means any codec
+func (d *Cid) GetCodec() uint64 {
+	return d.codec
+}
+
 // Link returns a link object for IPLD
 func (d *Cid) Link() (*node.Link, error) {
 	_, c, err := cid.CidFromBytes(d.c)
@@ -68,25 +90,50 @@ func (d *Cid) Set(obj interface{}) error {
 
 // Encode Cid
 func (d *Cid) Encode() (interface{}, error) {
+	if d.isLink {
+		_, c, err := cid.CidFromBytes(d.c)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+
 	return d.c, nil
 }
 
 // Decode Cid
 func (d *Cid) Decode(obj interface{}) (interface{}, error) {
-	c, ok := obj.([]byte)
-	if !ok {
+	var value cid.Cid
+	switch v := obj.(type) {
+	case []byte:
+		if d.isLink {
+			return nil, fmt.Errorf("Cid: link is expected but byte string is found")
+		}
+
+		_, c, err := cid.CidFromBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		value = c
+	case cid.Cid:
+		if !d.isLink {
+			return nil, fmt.Errorf("Cid: byte string is expected but link is found")
+		}
+		value = v
+	default:
+		expected := "[]byte"
+		if d.isLink {
+			expected = "cid.Cid"
+		}
+
 		return nil,
 			fmt.Errorf("Unknown error during decoding Cid: "+
-				"'[]byte' is expected but '%T' is found",
+				"'%s' is expected but '%T' is found",
+				expected,
 				obj,
 			)
 	}
 
-	_, value, err := cid.CidFromBytes(c)
-	if err != nil {
-		return nil, err
-	}
-
 	if d.codec != 0 && value.Type() != d.codec {
 		return nil,
 			fmt.Errorf(
@@ -96,7 +143,7 @@ func (d *Cid) Decode(obj interface{}) (interface{}, error) {
 			)
 	}
 
-	d.c = c
+	d.c = value.Bytes()
 	d.Base.MarkDefined()
 	return value, nil
 }
@@ -129,3 +176,11 @@ func (d *Cid) Resolve(path []string) (interface{}, []string, error) {
 
 	return link, path, nil
 }
+
+// Describe returns the descriptor of Cid
+func (d *Cid) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindCid, d.IsRequired())
+	desc.Codec = d.codec
+	desc.Link = d.isLink
+	return desc
+}
@@ -0,0 +1,113 @@
+package data
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+const (
+	codecA = 0x0264
+	codecB = 0x0265
+)
+
+func testCid(t *testing.T, codec uint64) cid.Cid {
+	h, err := mh.Sum([]byte("data"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cid.NewCidV1(codec, h)
+}
+
+// Links are encoded as cid.Cid, which is tag 42 in CBOR, and the others as
+// byte strings, each is only decoded from its own form
+func TestCidForms(t *testing.T) {
+	c := testCid(t, codecA)
+
+	for _, d := range []*Cid{NewCid("c", true, codecA), NewLink("c", true, codecA)} {
+		if err := d.Set(c); err != nil {
+			t.Fatal(err)
+		}
+
+		enc, err := d.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var wrong interface{} = c
+		if d.IsLink() {
+			if e, ok := enc.(cid.Cid); !ok || !e.Equals(c) {
+				t.Errorf("Encode: link %s is expected but %#v is found", c, enc)
+			}
+			wrong = c.Bytes()
+		} else {
+			if e, ok := enc.([]byte); !ok || !bytes.Equal(e, c.Bytes()) {
+				t.Errorf("Encode: byte string is expected but %#v is found", enc)
+			}
+		}
+
+		dec, err := d.Prototype().Decode(enc)
+		if err != nil {
+			t.Errorf("Decode (link %v): %s", d.IsLink(), err)
+		} else if !dec.(cid.Cid).Equals(c) {
+			t.Errorf("Decode (link %v): %s is expected but %s is found", d.IsLink(), c, dec)
+		}
+
+		if _, err := d.Prototype().Decode(wrong); err == nil {
+			t.Errorf("Decode (link %v): error is expected for %T", d.IsLink(), wrong)
+		}
+
+		for _, obj := range []interface{}{c.String(), nil, []byte{0x01}} {
+			if _, err := d.Prototype().Decode(obj); err == nil {
+				t.Errorf("Decode (link %v): error is expected for %#v", d.IsLink(), obj)
+			}
+		}
+	}
+}
+
+// The codec of the linked block is checked in both forms unless it is 0
+func TestCidCodec(t *testing.T) {
+	a, b := testCid(t, codecA), testCid(t, codecB)
+
+	for _, isLink := range []bool{false, true} {
+		newCid := NewCid
+		if isLink {
+			newCid = NewLink
+		}
+
+		d := newCid("c", true, codecA)
+		if err := d.Set(b); err == nil {
+			t.Errorf("Set (link %v): error is expected for codec 0x%x", isLink, codecB)
+		}
+		if d.IsDefined() {
+			t.Errorf("Set (link %v): Cid is defined after a failed Set", isLink)
+		}
+
+		var encoded interface{} = b.Bytes()
+		if isLink {
+			encoded = b
+		}
+		if _, err := d.Decode(encoded); err == nil {
+			t.Errorf("Decode (link %v): error is expected for codec 0x%x", isLink, codecB)
+		}
+
+		anyCodec := newCid("c", true, 0)
+		for _, c := range []cid.Cid{a, b} {
+			if err := anyCodec.Set(c); err != nil {
+				t.Errorf("Set (link %v): %s", isLink, err)
+			}
+		}
+
+		if err := d.Set("not a cid"); err == nil {
+			t.Errorf("Set (link %v): error is expected for a string", isLink)
+		}
+
+		desc := d.Describe()
+		if desc.Link != isLink || desc.Codec != codecA {
+			t.Errorf("Describe (link %v): link %v with codec 0x%x is found", isLink, desc.Link, desc.Codec)
+		}
+	}
+}
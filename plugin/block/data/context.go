@@ -88,6 +88,13 @@ func (d *Context) Resolve(path []string) (interface{}, []string, error) {
 	return d.getSchema(), nil, nil
 }
 
+// Describe returns the descriptor of Context
+func (d *Context) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindContext, d.IsRequired())
+	desc.Schema = d.schema
+	return desc
+}
+
 func (d *Context) getSchema() string {
 	return fmt.Sprintf("%s-v%d", d.schema, d.version)
 }
@@ -0,0 +1,206 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ==================================================
+// Decimal
+// ==================================================
+
+// decimalPattern is the form of a decimal without exponent, plus sign and
+// redundant leading zeros
+var decimalPattern = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?$`)
+
+// Decimal is a data handler for the arbitrary-precision decimal number, which
+// is encoded as a string in the canonical form without trailing zeros after
+// the decimal point, e.g. "-12.5", so that equal decimals are encoded equally
+type Decimal struct {
+	*Base
+
+	// precision is the maximum number of digits and scale is the maximum number
+	// of digits after the decimal point, 0 means unlimited
+	precision int
+	scale     int
+
+	value string
+}
+
+var _ Data = (*Decimal)(nil)
+
+// NewDecimal creates a decimal data handler, e.g. a precision of 10 and a
+// scale of 2 allows 8 digits before the decimal point and 2 after
+func NewDecimal(key string, isRequired bool, precision int, scale int) *Decimal {
+	return &Decimal{
+		Base:      NewBase(key, isRequired),
+		precision: precision,
+		scale:     scale,
+	}
+}
+
+// Prototype creates a prototype Decimal
+func (d *Decimal) Prototype() Data {
+	return &Decimal{
+		Base:      d.Base.Prototype(),
+		precision: d.precision,
+		scale:     d.scale,
+	}
+}
+
+// Get returns the decimal in the canonical string form
+func (d *Decimal) Get() string {
+	return d.value
+}
+
+// GetPrecision returns the maximum number of digits, 0 means unlimited
+func (d *Decimal) GetPrecision() int {
+	return d.precision
+}
+
+// GetScale returns the maximum number of digits after the decimal point, 0
+// means unlimited
+func (d *Decimal) GetScale() int {
+	return d.scale
+}
+
+// Set the value of Decimal, strings, integers and floats are accepted and
+// the trailing zeros after the decimal point are removed
+func (d *Decimal) Set(obj interface{}) error {
+	var value string
+	switch v := obj.(type) {
+	case string:
+		value = v
+	case json.Number:
+		value = v.String()
+	case int:
+		value = strconv.FormatInt(int64(v), 10)
+	case int32:
+		value = strconv.FormatInt(int64(v), 10)
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case uint:
+		value = strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		value = strconv.FormatUint(uint64(v), 10)
+	case uint64:
+		value = strconv.FormatUint(v, 10)
+	case float64:
+		// The shortest form which is parsed back to the same float
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("Decimal: 'string' is expected but '%T' is found", obj)
+	}
+
+	value = normalizeDecimal(value)
+	if err := d.validate(value); err != nil {
+		return err
+	}
+
+	d.value = value
+	d.Base.MarkDefined()
+	return nil
+}
+
+// normalizeDecimal removes the trailing zeros after the decimal point
+func normalizeDecimal(value string) string {
+	if !decimalPattern.MatchString(value) || !strings.ContainsRune(value, '.') {
+		return value
+	}
+
+	value = strings.TrimRight(value, "0")
+	return strings.TrimSuffix(value, ".")
+}
+
+// validate checks the canonical form, the precision and the scale
+func (d *Decimal) validate(value string) error {
+	isNegativeZero := strings.HasPrefix(value, "-") && strings.Trim(value, "-0.") == ""
+	if !decimalPattern.MatchString(value) || isNegativeZero {
+		return fmt.Errorf("Decimal: %q is not a decimal in the canonical form", value)
+	}
+
+	integer := strings.TrimPrefix(value, "-")
+	fraction := ""
+	if i := strings.IndexByte(integer, '.'); i >= 0 {
+		integer, fraction = integer[:i], integer[i+1:]
+	}
+
+	if integer == "0" {
+		integer = ""
+	}
+
+	if d.scale > 0 && len(fraction) > d.scale {
+		return fmt.Errorf(
+			"Decimal: %q has more than %d digits after the decimal point",
+			value,
+			d.scale,
+		)
+	}
+
+	digits := len(integer) + len(fraction)
+	if d.scale > 0 {
+		// The digits after the decimal point are reserved
+		digits = len(integer) + d.scale
+	}
+
+	if d.precision > 0 && digits > d.precision {
+		return fmt.Errorf("Decimal: %q exceeds the precision %d", value, d.precision)
+	}
+
+	return nil
+}
+
+// Encode Decimal
+func (d *Decimal) Encode() (interface{}, error) {
+	return d.value, nil
+}
+
+// Decode Decimal
+func (d *Decimal) Decode(obj interface{}) (interface{}, error) {
+	value, ok := obj.(string)
+	if !ok {
+		return nil,
+			fmt.Errorf("Unknown error during decoding Decimal: "+
+				"'string' is expected but '%T' is found",
+				obj,
+			)
+	}
+
+	// The stored decimal is in the canonical form, otherwise the block is
+	// encoded differently from the same decimal
+	if normalizeDecimal(value) != value {
+		return nil, fmt.Errorf("Decimal: %q is not a decimal in the canonical form", value)
+	}
+
+	if err := d.Set(value); err != nil {
+		return nil, err
+	}
+
+	return d.value, nil
+}
+
+// ToJSON prepares the data for MarshalJSON, the decimal is presented as a
+// string to keep the precision
+func (d *Decimal) ToJSON() (interface{}, error) {
+	return d.value, nil
+}
+
+// Resolve resolves the value
+func (d *Decimal) Resolve(path []string) (interface{}, []string, error) {
+	if len(path) != 0 {
+		return nil, nil, fmt.Errorf("Unexpected path elements past %s", path[0])
+	}
+
+	return d.value, nil, nil
+}
+
+// Describe returns the descriptor of Decimal
+func (d *Decimal) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindDecimal, d.IsRequired())
+	desc.Precision = d.precision
+	desc.Scale = d.scale
+	return desc
+}
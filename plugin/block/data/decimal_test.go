@@ -0,0 +1,99 @@
+package data
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecimalSet(t *testing.T) {
+	cases := []struct {
+		precision int
+		scale     int
+		value     interface{}
+		expected  string
+	}{
+		{0, 0, "-12.5", "-12.5"},
+		{0, 0, "0", "0"},
+		{0, 0, "0.001", "0.001"},
+		{0, 0, 42, "42"},
+		{0, 0, uint64(42), "42"},
+		{0, 0, 0.1, "0.1"},
+		{0, 0, json.Number("3.14"), "3.14"},
+
+		// Equal decimals are in the same form
+		{0, 0, "1.50", "1.5"},
+		{0, 0, "1.0", "1"},
+		{0, 0, "100", "100"},
+		{0, 0, "0.00", "0"},
+		{0, 2, "1.500", "1.5"},
+
+		{0, 0, "+1", ""},
+		{0, 0, "01", ""},
+		{0, 0, "1.", ""},
+		{0, 0, ".5", ""},
+		{0, 0, "1e5", ""},
+		{0, 0, "-0", ""},
+		{0, 0, "-0.0", ""},
+		{0, 0, true, ""},
+
+		{10, 2, "12345678.99", "12345678.99"},
+		{10, 2, "123456789", ""},
+		{10, 2, "0.123", ""},
+		{3, 0, "12.3", "12.3"},
+		{3, 0, "1.234", ""},
+	}
+
+	for _, c := range cases {
+		d := NewDecimal("d", true, c.precision, c.scale)
+		err := d.Set(c.value)
+		if c.expected == "" {
+			if err == nil {
+				t.Errorf("Set(%#v): error is expected but %q is found", c.value, d.Get())
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Set(%#v): %s", c.value, err)
+			continue
+		}
+
+		if d.Get() != c.expected {
+			t.Errorf("Set(%#v): %q is expected but %q is found", c.value, c.expected, d.Get())
+		}
+	}
+}
+
+// The stored decimals are in the canonical form, so that each decimal has
+// only one encoding
+func TestDecimalDecode(t *testing.T) {
+	d := NewDecimal("d", true, 0, 0)
+
+	value, err := d.Decode("-12.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "-12.5" {
+		t.Errorf("Decode(%q): %v is found", "-12.5", value)
+	}
+
+	for _, obj := range []interface{}{"-12.50", "1.0", "01", uint64(1), 1.5} {
+		if _, err := d.Decode(obj); err == nil {
+			t.Errorf("Decode(%#v): error is expected", obj)
+		}
+	}
+
+	a, b := NewDecimal("d", true, 0, 0), NewDecimal("d", true, 0, 0)
+	if err := a.Set("1.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Set("1.50"); err != nil {
+		t.Fatal(err)
+	}
+
+	encA, _ := a.Encode()
+	encB, _ := b.Encode()
+	if encA != encB {
+		t.Errorf("Encode: 1.5 and 1.50 are encoded as %q and %q", encA, encB)
+	}
+}
@@ -0,0 +1,139 @@
+package data
+
+// ==================================================
+// Descriptor
+// ==================================================
+
+// Kind is the kind of a data handler
+type Kind string
+
+// Kinds of the data handlers
+const (
+	KindString          Kind = "string"
+	KindPatternString   Kind = "patternString"
+	KindFilterString    Kind = "filterString"
+	KindTimestamp       Kind = "timestamp"
+	KindURL             Kind = "url"
+	KindHash            Kind = "hash"
+	KindLikeCoinChainID Kind = "likecoinChainID"
+	KindNumber          Kind = "number"
+	KindBool            Kind = "bool"
+	KindFloat64         Kind = "float64"
+	KindDecimal         Kind = "decimal"
+	KindCid             Kind = "cid"
+	KindArray           Kind = "array"
+	KindMap             Kind = "map"
+	KindLanguageTag     Kind = "languageTag"
+	KindLocalizedString Kind = "localizedString"
+	KindObject          Kind = "object"
+	KindContext         Kind = "context"
+	KindUnion           Kind = "union"
+	KindUnknown         Kind = "unknown"
+)
+
+// Descriptor describes a data handler for introspection
+type Descriptor struct {
+	Key      string `json:"key"`
+	Kind     Kind   `json:"kind"`
+	Required bool   `json:"required"`
+
+	// NumberType is the type of a number
+	NumberType string `json:"numberType,omitempty"`
+
+	// Native is true if the number is encoded as a native CBOR integer
+	Native bool `json:"native,omitempty"`
+
+	// Precision is the maximum number of digits of a decimal and Scale is the
+	// maximum number of digits after the decimal point
+	Precision int `json:"precision,omitempty"`
+	Scale     int `json:"scale,omitempty"`
+
+	// Codec is the codec of the linked block of a CID, 0 means any codec
+	Codec uint64 `json:"codec,omitempty"`
+
+	// Link is true if the CID is encoded as a tag 42 link
+	Link bool `json:"link,omitempty"`
+
+	// Schema is the schema name of the linked block of a CID or of a nested
+	// object
+	Schema string `json:"schema,omitempty"`
+
+	// Pattern is the regular expression of a pattern string
+	Pattern string `json:"pattern,omitempty"`
+
+	// Enum is the allowed values of a filter string
+	Enum []string `json:"enum,omitempty"`
+
+	// Elem describes the elements of an array or the values of a map
+	Elem *Descriptor `json:"elem,omitempty"`
+
+	// Keys describes the keys of a map
+	Keys *Descriptor `json:"keys,omitempty"`
+
+	// Language is the default language of a localized string
+	Language string `json:"language,omitempty"`
+
+	// Fields describes the properties of a nested object
+	Fields []*Descriptor `json:"fields,omitempty"`
+
+	// Variants describes the alternatives of a union
+	Variants []*Descriptor `json:"variants,omitempty"`
+
+	// Tag is the key of a variant of a keyed union
+	Tag string `json:"tag,omitempty"`
+}
+
+// Describer is the interface of the data handlers supporting introspection
+type Describer interface {
+	Describe() *Descriptor
+}
+
+// NewDescriptor creates a descriptor of a data handler
+func NewDescriptor(key string, kind Kind, isRequired bool) *Descriptor {
+	return &Descriptor{
+		Key:      key,
+		Kind:     kind,
+		Required: isRequired,
+	}
+}
+
+// Describe returns the descriptor of the data handler, handlers not
+// implementing Describer are described as KindUnknown
+func Describe(d Data) *Descriptor {
+	if describer, ok := d.(Describer); ok {
+		return describer.Describe()
+	}
+
+	return NewDescriptor(d.GetKey(), KindUnknown, d.IsRequired())
+}
+
+// DescribeSchema returns the descriptors of the schema
+func DescribeSchema(schema []Data) []*Descriptor {
+	res := []*Descriptor{}
+	for _, d := range schema {
+		res = append(res, Describe(d))
+	}
+	return res
+}
+
+// Walk calls fn for the descriptor and all its descendants in depth-first
+// order
+func (d *Descriptor) Walk(fn func(*Descriptor)) {
+	fn(d)
+
+	if d.Elem != nil {
+		d.Elem.Walk(fn)
+	}
+
+	if d.Keys != nil {
+		d.Keys.Walk(fn)
+	}
+
+	for _, field := range d.Fields {
+		field.Walk(fn)
+	}
+
+	for _, variant := range d.Variants {
+		variant.Walk(fn)
+	}
+}
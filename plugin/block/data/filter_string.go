@@ -2,6 +2,7 @@ package data
 
 import (
 	"fmt"
+	"sort"
 )
 
 // ==================================================
@@ -84,3 +85,16 @@ func (d *FilterString) ToJSON() (interface{}, error) {
 func (d *FilterString) Resolve(path []string) (interface{}, []string, error) {
 	return d.value.Resolve(path)
 }
+
+// Describe returns the descriptor of FilterString
+func (d *FilterString) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindFilterString, d.IsRequired())
+
+	desc.Enum = []string{}
+	for value := range d.filter {
+		desc.Enum = append(desc.Enum, value)
+	}
+	sort.Strings(desc.Enum)
+
+	return desc
+}
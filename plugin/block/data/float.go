@@ -0,0 +1,138 @@
+package data
+
+import (
+	"fmt"
+	"math"
+)
+
+// ==================================================
+// Float64
+// ==================================================
+
+// maxSafeInteger is the largest integer which float64 represents exactly
+const maxSafeInteger = 1 << 53
+
+// Float64 is a data handler for the 64-bit floating point number, NaN and
+// infinities are not allowed as they have no deterministic encoding in IPLD
+type Float64 struct {
+	*Base
+
+	value float64
+}
+
+var _ Data = (*Float64)(nil)
+
+// NewFloat64 creates a 64-bit floating point number data handler
+func NewFloat64(key string, isRequired bool) *Float64 {
+	return &Float64{
+		Base: NewBase(key, isRequired),
+	}
+}
+
+// Prototype creates a prototype Float64
+func (d *Float64) Prototype() Data {
+	return &Float64{
+		Base: d.Base.Prototype(),
+	}
+}
+
+// Get returns the float64 value
+func (d *Float64) Get() float64 {
+	return d.value
+}
+
+// Set the value of Float64, integers are accepted as long as they are
+// represented exactly
+func (d *Float64) Set(obj interface{}) error {
+	var value float64
+	switch v := obj.(type) {
+	case float32:
+		value = float64(v)
+	case float64:
+		value = v
+	case int:
+		if v < -maxSafeInteger || maxSafeInteger < v {
+			return fmt.Errorf("Float64: %d is out of the range of exact integers", v)
+		}
+		value = float64(v)
+	case int8:
+		value = float64(v)
+	case int16:
+		value = float64(v)
+	case int32:
+		value = float64(v)
+	case int64:
+		if v < -maxSafeInteger || maxSafeInteger < v {
+			return fmt.Errorf("Float64: %d is out of the range of exact integers", v)
+		}
+		value = float64(v)
+	case uint:
+		if v > maxSafeInteger {
+			return fmt.Errorf("Float64: %d is out of the range of exact integers", v)
+		}
+		value = float64(v)
+	case uint8:
+		value = float64(v)
+	case uint16:
+		value = float64(v)
+	case uint32:
+		value = float64(v)
+	case uint64:
+		if v > maxSafeInteger {
+			return fmt.Errorf("Float64: %d is out of the range of exact integers", v)
+		}
+		value = float64(v)
+	default:
+		return fmt.Errorf("Float64: 'float64' is expected but '%T' is found", obj)
+	}
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("Float64: %v is not allowed", value)
+	}
+
+	d.value = value
+	d.Base.MarkDefined()
+	return nil
+}
+
+// Encode Float64
+func (d *Float64) Encode() (interface{}, error) {
+	return d.value, nil
+}
+
+// Decode Float64
+func (d *Float64) Decode(obj interface{}) (interface{}, error) {
+	value, ok := obj.(float64)
+	if !ok {
+		return nil,
+			fmt.Errorf("Unknown error during decoding Float64: "+
+				"'float64' is expected but '%T' is found",
+				obj,
+			)
+	}
+
+	if err := d.Set(value); err != nil {
+		return nil, err
+	}
+
+	return d.value, nil
+}
+
+// ToJSON prepares the data for MarshalJSON
+func (d *Float64) ToJSON() (interface{}, error) {
+	return d.value, nil
+}
+
+// Resolve resolves the value
+func (d *Float64) Resolve(path []string) (interface{}, []string, error) {
+	if len(path) != 0 {
+		return nil, nil, fmt.Errorf("Unexpected path elements past %s", path[0])
+	}
+
+	return d.value, nil, nil
+}
+
+// Describe returns the descriptor of Float64
+func (d *Float64) Describe() *Descriptor {
+	return NewDescriptor(d.GetKey(), KindFloat64, d.IsRequired())
+}
@@ -0,0 +1,67 @@
+package data
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFloat64Set(t *testing.T) {
+	cases := []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{1.5, 1.5},
+		{float32(0.5), 0.5},
+		{-3, -3.0},
+		{uint8(255), 255.0},
+		{int64(maxSafeInteger), float64(maxSafeInteger)},
+		{int64(-maxSafeInteger), float64(-maxSafeInteger)},
+		{uint64(maxSafeInteger), float64(maxSafeInteger)},
+		{int64(maxSafeInteger + 1), nil},
+		{int64(-maxSafeInteger - 1), nil},
+		{uint64(maxSafeInteger + 1), nil},
+		{math.NaN(), nil},
+		{math.Inf(1), nil},
+		{math.Inf(-1), nil},
+		{"1.5", nil},
+	}
+
+	for _, c := range cases {
+		d := NewFloat64("f", true)
+		err := d.Set(c.value)
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("Set(%v): error is expected but %v is found", c.value, d.Get())
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Set(%v): %s", c.value, err)
+			continue
+		}
+
+		if d.Get() != c.expected {
+			t.Errorf("Set(%v): %v is expected but %v is found", c.value, c.expected, d.Get())
+		}
+	}
+}
+
+// Only CBOR floats are decoded, integers are not
+func TestFloat64Decode(t *testing.T) {
+	d := NewFloat64("f", true)
+
+	value, err := d.Decode(2.25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != 2.25 {
+		t.Errorf("Decode(2.25): %v is found", value)
+	}
+
+	for _, obj := range []interface{}{uint64(2), int64(-2), float32(2.25)} {
+		if _, err := d.Decode(obj); err == nil {
+			t.Errorf("Decode(%T): error is expected", obj)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gitlab.com/c0b/go-ordered-json"
+)
+
+// ==================================================
+// LanguageTag
+// ==================================================
+
+// UndeterminedLanguage is the BCP 47 tag of an undetermined language
+const UndeterminedLanguage = "und"
+
+// languageTagPattern is the well-formed BCP 47 language tag in the canonical
+// case: language, script, region, variants, extensions and private use, or
+// one of the irregular grandfathered tags
+const languageTagPattern = `^(?:` +
+	`(?:[a-z]{2,3}(?:-[a-z]{3}){0,3}|[a-z]{4,8})` +
+	`(?:-[A-Z][a-z]{3})?` +
+	`(?:-(?:[A-Z]{2}|[0-9]{3}))?` +
+	`(?:-(?:[0-9a-z]{5,8}|[0-9][0-9a-z]{3}))*` +
+	`(?:-[0-9a-wyz](?:-[0-9a-z]{2,8})+)*` +
+	`(?:-x(?:-[0-9a-z]{1,8})+)?` +
+	`|x(?:-[0-9a-z]{1,8})+` +
+	`|en-GB-oed|sgn-BE-FR|sgn-BE-NL|sgn-CH-DE` +
+	`|i-(?:ami|bnn|default|enochian|hak|klingon|lux|mingo|navajo|pwn|tao|tay|tsu))$`
+
+var languageTagRegexp = regexp.MustCompile(languageTagPattern)
+
+// LanguageTag is a data handler for a BCP 47 language tag in the canonical
+// case, e.g. "en", "zh-Hant-TW" and "ja-JP"
+type LanguageTag struct {
+	*PatternString
+}
+
+var _ Data = (*LanguageTag)(nil)
+
+// NewLanguageTag creates a BCP 47 language tag handler
+func NewLanguageTag(key string, isRequired bool) *LanguageTag {
+	return &LanguageTag{
+		PatternString: NewPatternString(key, isRequired, languageTagPattern),
+	}
+}
+
+// Prototype creates a prototype LanguageTag
+func (d *LanguageTag) Prototype() Data {
+	return &LanguageTag{
+		PatternString: NewPatternString(d.GetKey(), d.IsRequired(), d.pattern.String()),
+	}
+}
+
+// Describe returns the descriptor of LanguageTag
+func (d *LanguageTag) Describe() *Descriptor {
+	desc := d.PatternString.Describe()
+	desc.Kind = KindLanguageTag
+	return desc
+}
+
+// CanonicalLanguageTag converts the BCP 47 language tag to the canonical case,
+// i.e. lower case except title case scripts and upper case regions, and
+// checks whether it is well-formed
+func CanonicalLanguageTag(tag string) (string, error) {
+	subtags := strings.Split(tag, "-")
+	for i, subtag := range subtags {
+		subtags[i] = strings.ToLower(subtag)
+	}
+
+	// Scripts and regions only appear before the first singleton
+	for i := 1; i < len(subtags) && len(subtags[i]) > 1; i++ {
+		subtag := subtags[i]
+		switch {
+		case len(subtag) == 4 && isAlpha(subtag):
+			subtags[i] = strings.ToUpper(subtag[:1]) + subtag[1:]
+		case len(subtag) == 2 && isAlpha(subtag):
+			subtags[i] = strings.ToUpper(subtag)
+		}
+	}
+
+	res := strings.Join(subtags, "-")
+	if !languageTagRegexp.MatchString(res) {
+		return "", fmt.Errorf("LanguageTag: %q is not a well-formed BCP 47 language tag", tag)
+	}
+	return res, nil
+}
+
+func isAlpha(s string) bool {
+	for _, r := range s {
+		if r < 'a' || 'z' < r {
+			return false
+		}
+	}
+	return true
+}
+
+// ==================================================
+// LocalizedString
+// ==================================================
+
+// LocalizedString is a data handler for a string in multiple languages,
+// which is encoded as a map of BCP 47 language tags to strings, e.g.
+// {"en": "...", "ja": "..."}. A plain string is in the default language
+type LocalizedString struct {
+	*Map
+
+	defaultLanguage string
+}
+
+var _ Data = (*LocalizedString)(nil)
+
+// NewLocalizedString creates a localized string data handler, the default
+// language is the language of plain strings and the last resort of Lookup
+func NewLocalizedString(key string, isRequired bool, defaultLanguage string) *LocalizedString {
+	language, err := CanonicalLanguageTag(defaultLanguage)
+	if err != nil {
+		panic(fmt.Sprintf("LocalizedString: invalid default language (%s)", err))
+	}
+
+	return &LocalizedString{
+		Map: NewDataMap(
+			key,
+			isRequired,
+			NewLanguageTag("_", true),
+			NewString("_", true),
+		),
+		defaultLanguage: language,
+	}
+}
+
+// Prototype creates a prototype LocalizedString
+func (d *LocalizedString) Prototype() Data {
+	return &LocalizedString{
+		Map:             d.Map.Prototype().(*Map),
+		defaultLanguage: d.defaultLanguage,
+	}
+}
+
+// GetDefaultLanguage returns the default language
+func (d *LocalizedString) GetDefaultLanguage() string {
+	return d.defaultLanguage
+}
+
+// Strings returns the strings by their language tags
+func (d *LocalizedString) Strings() map[string]string {
+	res := map[string]string{}
+	for _, tag := range d.Keys() {
+		value, _ := d.Map.Get(tag)
+		res[tag] = value.(*String).Get()
+	}
+	return res
+}
+
+// Lookup returns the string of the first matched language in the order of
+// preference and the language tag of the string. A language matches its
+// more specific tags, e.g. "zh-Hant" matches "zh-Hant-TW", and falls back
+// to its less specific tags, e.g. "zh-Hant-TW" to "zh-Hant" and "zh". The
+// default language and then the first language in order are the last resort
+func (d *LocalizedString) Lookup(languages ...string) (string, string, bool) {
+	strs := d.Strings()
+	if len(strs) == 0 {
+		return "", "", false
+	}
+
+	tags := d.Keys()
+	preferences := append(append([]string{}, languages...), d.defaultLanguage)
+	for _, language := range preferences {
+		language, err := CanonicalLanguageTag(language)
+		if err != nil {
+			continue
+		}
+
+		for candidate := language; candidate != ""; candidate = truncateLanguageTag(candidate) {
+			if value, ok := strs[candidate]; ok {
+				return value, candidate, true
+			}
+
+			for _, tag := range tags {
+				if strings.HasPrefix(tag, candidate+"-") {
+					return strs[tag], tag, true
+				}
+			}
+		}
+	}
+
+	return strs[tags[0]], tags[0], true
+}
+
+// truncateLanguageTag removes the last subtag of the language tag, together
+// with a singleton left at the end
+func truncateLanguageTag(tag string) string {
+	i := strings.LastIndexByte(tag, '-')
+	if i < 0 {
+		return ""
+	}
+
+	tag = tag[:i]
+	if i = strings.LastIndexByte(tag, '-'); i >= 0 && len(tag)-i == 2 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// Set the value of LocalizedString, which is a plain string, a map of
+// language tags to strings or a list of JSON-LD value objects with
+// "@value" and "@language"
+func (d *LocalizedString) Set(obj interface{}) error {
+	strs := map[string]interface{}{}
+	add := func(tag string, value interface{}) error {
+		language, err := CanonicalLanguageTag(tag)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := strs[language]; ok {
+			return fmt.Errorf("LocalizedString: language %q is duplicated", language)
+		}
+		strs[language] = value
+		return nil
+	}
+
+	switch v := obj.(type) {
+	case string:
+		strs[d.defaultLanguage] = v
+	case []interface{}:
+		for i, elem := range v {
+			m, ok := elem.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("(Index %d) LocalizedString: a value object is expected but '%T' is found", i, elem)
+			}
+
+			tag, ok := m["@language"].(string)
+			if !ok {
+				tag = d.defaultLanguage
+			}
+
+			if err := add(tag, m["@value"]); err != nil {
+				return fmt.Errorf("(Index %d) %s", i, err.Error())
+			}
+		}
+	default:
+		m := reflect.ValueOf(obj)
+		if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("LocalizedString: 'string' or a map is expected but '%T' is found", obj)
+		}
+
+		iter := m.MapRange()
+		for iter.Next() {
+			if err := add(iter.Key().String(), iter.Value().Interface()); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(strs) == 0 {
+		return fmt.Errorf("LocalizedString: at least one language is expected")
+	}
+
+	return d.Map.Set(strs)
+}
+
+// Decode LocalizedString
+func (d *LocalizedString) Decode(obj interface{}) (interface{}, error) {
+	dec, err := d.Map.Decode(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d.entries) == 0 {
+		return nil, fmt.Errorf("LocalizedString: at least one language is expected")
+	}
+	return dec, nil
+}
+
+// ToJSON prepares the data for MarshalJSON, the strings are JSON-LD value
+// objects with "@language" in the order of the language tags
+func (d *LocalizedString) ToJSON() (interface{}, error) {
+	res := []interface{}{}
+	strs := d.Strings()
+	for _, tag := range d.Keys() {
+		om := ordered.NewOrderedMap()
+		om.Set("@value", strs[tag])
+		om.Set("@language", tag)
+		res = append(res, om)
+	}
+
+	return res, nil
+}
+
+// Describe returns the descriptor of LocalizedString
+func (d *LocalizedString) Describe() *Descriptor {
+	desc := d.Map.Describe()
+	desc.Kind = KindLocalizedString
+	desc.Language = d.defaultLanguage
+	return desc
+}
@@ -0,0 +1,218 @@
+package data
+
+import (
+	"testing"
+)
+
+func TestCanonicalLanguageTag(t *testing.T) {
+	cases := []struct {
+		tag      string
+		expected string
+	}{
+		{"en", "en"},
+		{"EN-us", "en-US"},
+		{"zh-hant-tw", "zh-Hant-TW"},
+		{"es-419", "es-419"},
+		{"sl-rozaj-biske", "sl-rozaj-biske"},
+		{"de-CH-1901", "de-CH-1901"},
+		{"en-a-bbb-x-a-ccc", "en-a-bbb-x-a-ccc"},
+		{"x-whatever", "x-whatever"},
+		{"zh-yue-HK", "zh-yue-HK"},
+		{"und", "und"},
+		{"english", "english"},
+
+		// Singletons end the scripts and regions
+		{"en-u-ca-gregory-x-ab", "en-u-ca-gregory-x-ab"},
+		{"en-x-us", "en-x-us"},
+
+		// Grandfathered tags
+		{"i-klingon", "i-klingon"},
+		{"I-Klingon", "i-klingon"},
+		{"en-gb-oed", "en-GB-oed"},
+		{"sgn-BE-FR", "sgn-BE-FR"},
+		{"art-lojban", "art-lojban"},
+		{"zh-min-nan", "zh-min-nan"},
+		{"cel-gaulish", "cel-gaulish"},
+
+		// Malformed tags
+		{"", ""},
+		{"e", ""},
+		{"en_US", ""},
+		{"en-", ""},
+		{"-en", ""},
+		{"en--US", ""},
+		{"en-US-", ""},
+		{"i-unknown", ""},
+		{"en-a", ""},
+		{"en-x", ""},
+		{"en-abcdefghi", ""},
+		{"en-Latn-Latn", ""},
+		{"123", ""},
+		{"en-US-x-abcdefghi", ""},
+	}
+
+	for _, c := range cases {
+		res, err := CanonicalLanguageTag(c.tag)
+		if c.expected == "" {
+			if err == nil {
+				t.Errorf("CanonicalLanguageTag(%q): error is expected but %q is found", c.tag, res)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("CanonicalLanguageTag(%q): %s", c.tag, err)
+			continue
+		}
+
+		if res != c.expected {
+			t.Errorf("CanonicalLanguageTag(%q): %q is expected but %q is found", c.tag, c.expected, res)
+		}
+
+		if err := NewLanguageTag("l", true).Set(res); err != nil {
+			t.Errorf("LanguageTag.Set(%q): %s", res, err)
+		}
+	}
+}
+
+// Only the tags in the canonical case are stored
+func TestLanguageTagDecode(t *testing.T) {
+	d := NewLanguageTag("l", true)
+	for _, tag := range []string{"en-us", "ZH-Hant", "en_US"} {
+		if _, err := d.Decode(tag); err == nil {
+			t.Errorf("Decode(%q): error is expected", tag)
+		}
+	}
+}
+
+func TestLocalizedStringSet(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    interface{}
+		expected map[string]string
+	}{
+		{
+			"plain string",
+			"Title",
+			map[string]string{"en": "Title"},
+		},
+		{
+			"map",
+			map[string]interface{}{"EN": "Title", "zh-hant": "標題"},
+			map[string]string{"en": "Title", "zh-Hant": "標題"},
+		},
+		{
+			"value objects",
+			[]interface{}{
+				map[string]interface{}{"@value": "Title"},
+				map[string]interface{}{"@value": "タイトル", "@language": "ja"},
+			},
+			map[string]string{"en": "Title", "ja": "タイトル"},
+		},
+		{
+			"duplicated languages",
+			map[string]interface{}{"en": "a", "EN": "b"},
+			nil,
+		},
+		{
+			"duplicated default language",
+			[]interface{}{
+				map[string]interface{}{"@value": "a"},
+				map[string]interface{}{"@value": "b", "@language": "en"},
+			},
+			nil,
+		},
+		{
+			"malformed language",
+			map[string]interface{}{"en_US": "a"},
+			nil,
+		},
+		{
+			"no language",
+			map[string]interface{}{},
+			nil,
+		},
+		{
+			"not a string",
+			map[string]interface{}{"en": uint64(1)},
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		d := NewLocalizedString("s", true, "en")
+		err := d.Set(c.value)
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("%s: error is expected but %v is found", c.name, d.Strings())
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+
+		strs := d.Strings()
+		if len(strs) != len(c.expected) {
+			t.Errorf("%s: %v is expected but %v is found", c.name, c.expected, strs)
+			continue
+		}
+		for tag, value := range c.expected {
+			if strs[tag] != value {
+				t.Errorf("%s: %v is expected but %v is found", c.name, c.expected, strs)
+			}
+		}
+	}
+}
+
+func TestLocalizedStringDecode(t *testing.T) {
+	d := NewLocalizedString("s", true, "en")
+	for _, obj := range []interface{}{
+		"Title",
+		map[string]interface{}{},
+		map[string]interface{}{"EN": "Title"},
+		map[string]interface{}{"en": "Title", "i-unknown": "Title"},
+	} {
+		if _, err := d.Decode(obj); err == nil {
+			t.Errorf("Decode(%v): error is expected", obj)
+		}
+	}
+
+	if _, err := d.Decode(map[string]interface{}{"en": "Title", "i-klingon": "Title"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	d := NewLocalizedString("s", true, "en")
+	err := d.Set(map[string]interface{}{
+		"en":         "Title",
+		"zh-Hant-TW": "標題",
+		"ja":         "タイトル",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		languages []string
+		tag       string
+	}{
+		{[]string{"ja"}, "ja"},
+		{[]string{"zh-Hant"}, "zh-Hant-TW"},
+		{[]string{"zh-Hant-TW-x-private"}, "zh-Hant-TW"},
+		{[]string{"fr", "ja-JP"}, "ja"},
+		{[]string{"fr"}, "en"},
+		{[]string{"not_a_tag", "zh"}, "zh-Hant-TW"},
+		{nil, "en"},
+	}
+
+	for _, c := range cases {
+		_, tag, ok := d.Lookup(c.languages...)
+		if !ok || tag != c.tag {
+			t.Errorf("Lookup(%v): %q is expected but %q is found", c.languages, c.tag, tag)
+		}
+	}
+}
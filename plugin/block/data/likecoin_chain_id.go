@@ -0,0 +1,189 @@
+package data
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/likecoin/iscn-ipld/plugin/identifier"
+)
+
+// ==================================================
+// LikeCoinChainID
+// ==================================================
+
+// LikeCoinChainID is a data handler for the LikeCoin chain ID, i.e.
+// "lcc://id/" and a bech32 address whose checksum and 20-byte payload are
+// verified
+type LikeCoinChainID struct {
+	*PatternString
+
+	hrps    []string
+	hrp     string
+	address []byte
+}
+
+var _ Data = (*LikeCoinChainID)(nil)
+
+// NewLikeCoinChainID creates a LikeCoin chain ID handler, the human readable
+// part of the address should be one of the hrps, which is "cosmos" if none
+// is given
+func NewLikeCoinChainID(key string, isRequired bool, hrps ...string) *LikeCoinChainID {
+	if len(hrps) == 0 {
+		hrps = []string{identifier.LikeCoinHRP}
+	}
+
+	return &LikeCoinChainID{
+		PatternString: NewPatternString(key, isRequired, likeCoinChainIDPattern(hrps)),
+		hrps:          hrps,
+	}
+}
+
+// likeCoinChainIDPattern returns the pattern of the LikeCoin chain IDs with
+// the human readable parts
+func likeCoinChainIDPattern(hrps []string) string {
+	quoted := []string{}
+	for _, hrp := range hrps {
+		quoted = append(quoted, regexp.QuoteMeta(hrp))
+	}
+
+	return fmt.Sprintf(
+		`^%s(?:%s)1[02-9ac-hj-np-z]{38}$`,
+		regexp.QuoteMeta(identifier.LikeCoinPrefix),
+		strings.Join(quoted, "|"),
+	)
+}
+
+// Prototype creates a prototype LikeCoinChainID
+func (d *LikeCoinChainID) Prototype() Data {
+	return &LikeCoinChainID{
+		PatternString: NewPatternString(d.GetKey(), d.IsRequired(), d.pattern.String()),
+		hrps:          d.hrps,
+	}
+}
+
+// GetHRPs returns the accepted human readable parts
+func (d *LikeCoinChainID) GetHRPs() []string {
+	return d.hrps
+}
+
+// GetHRP returns the human readable part of the address
+func (d *LikeCoinChainID) GetHRP() string {
+	return d.hrp
+}
+
+// GetAddress returns the raw address bytes, which is nil if the decoded ID
+// has an invalid checksum
+func (d *LikeCoinChainID) GetAddress() []byte {
+	return d.address
+}
+
+// GetBech32 returns the bare bech32 address
+func (d *LikeCoinChainID) GetBech32() string {
+	return strings.TrimPrefix(d.Get(), identifier.LikeCoinPrefix)
+}
+
+// Set the value of LikeCoinChainID, which is a LikeCoin chain ID or a bare
+// bech32 address
+func (d *LikeCoinChainID) Set(obj interface{}) error {
+	value, ok := obj.(string)
+	if !ok {
+		return fmt.Errorf("LikeCoinChainID: 'string' is expected but '%T' is found", obj)
+	}
+
+	hrp, address, err := ParseLikeCoinChainID(value, d.hrps...)
+	if err != nil {
+		return err
+	}
+
+	id, err := FormatLikeCoinChainID(hrp, address)
+	if err != nil {
+		return err
+	}
+
+	if err := d.PatternString.Set(id); err != nil {
+		return err
+	}
+
+	d.hrp = hrp
+	d.address = address
+	return nil
+}
+
+// Decode LikeCoinChainID, the IDs stored before their checksums were verified
+// are only checked against the pattern, and their addresses are nil as they
+// cannot be trusted
+func (d *LikeCoinChainID) Decode(obj interface{}) (interface{}, error) {
+	if err := d.Set(obj); err != nil {
+		value, ok := obj.(string)
+		if !ok || d.PatternString.Set(value) != nil {
+			return nil, err
+		}
+
+		d.hrp = ""
+		d.address = nil
+	}
+
+	d.Base.MarkDefined()
+	return d.Get(), nil
+}
+
+// Describe returns the descriptor of LikeCoinChainID
+func (d *LikeCoinChainID) Describe() *Descriptor {
+	desc := d.PatternString.Describe()
+	desc.Kind = KindLikeCoinChainID
+	return desc
+}
+
+// ParseLikeCoinChainID parses the LikeCoin chain ID or the bare bech32
+// address, and returns the human readable part and the raw address bytes.
+// The human readable part should be one of the hrps, which is "cosmos" if
+// none is given
+func ParseLikeCoinChainID(id string, hrps ...string) (string, []byte, error) {
+	if len(hrps) == 0 {
+		hrps = []string{identifier.LikeCoinHRP}
+	}
+
+	hrp, address, err := identifier.DecodeAddress(
+		strings.TrimPrefix(id, identifier.LikeCoinPrefix),
+		hrps...,
+	)
+	if err != nil {
+		return "", nil, fmt.Errorf("LikeCoinChainID: %s", err)
+	}
+
+	return hrp, address, nil
+}
+
+// FormatLikeCoinChainID returns the LikeCoin chain ID of the raw address
+// bytes with the human readable part
+func FormatLikeCoinChainID(hrp string, address []byte) (string, error) {
+	bech, err := identifier.EncodeAddress(hrp, address)
+	if err != nil {
+		return "", fmt.Errorf("LikeCoinChainID: %s", err)
+	}
+
+	return identifier.LikeCoinPrefix + bech, nil
+}
+
+// LikeCoinChainIDToAddress converts the LikeCoin chain ID to the bare bech32
+// address
+func LikeCoinChainIDToAddress(id string, hrps ...string) (string, error) {
+	hrp, address, err := ParseLikeCoinChainID(id, hrps...)
+	if err != nil {
+		return "", err
+	}
+
+	return identifier.EncodeAddress(hrp, address)
+}
+
+// AddressToLikeCoinChainID converts the bare bech32 address to the LikeCoin
+// chain ID
+func AddressToLikeCoinChainID(address string, hrps ...string) (string, error) {
+	hrp, raw, err := ParseLikeCoinChainID(address, hrps...)
+	if err != nil {
+		return "", err
+	}
+
+	return FormatLikeCoinChainID(hrp, raw)
+}
@@ -0,0 +1,217 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"gitlab.com/c0b/go-ordered-json"
+)
+
+// ==================================================
+// Map
+// ==================================================
+
+// Map is a map of data handler, the keys are strings validated by the key
+// prototype and the values are validated by the value prototype, e.g.
+// localized titles {"en": "...", "ja": "..."}
+type Map struct {
+	*Base
+
+	entries        map[string]Data
+	keyPrototype   Data
+	valuePrototype Data
+}
+
+var _ Data = (*Map)(nil)
+
+// NewDataMap creates a map of data handler
+func NewDataMap(key string, isRequired bool, keyPrototype Data, valuePrototype Data) *Map {
+	return &Map{
+		Base:           NewBase(key, isRequired),
+		entries:        map[string]Data{},
+		keyPrototype:   keyPrototype,
+		valuePrototype: valuePrototype,
+	}
+}
+
+// Prototype creates a prototype Map
+func (d *Map) Prototype() Data {
+	return &Map{
+		Base:           d.Base.Prototype(),
+		entries:        map[string]Data{},
+		keyPrototype:   d.keyPrototype.Prototype(),
+		valuePrototype: d.valuePrototype.Prototype(),
+	}
+}
+
+// GetKeyPrototype returns the prototype of the keys
+func (d *Map) GetKeyPrototype() Data {
+	return d.keyPrototype
+}
+
+// GetValuePrototype returns the prototype of the values
+func (d *Map) GetValuePrototype() Data {
+	return d.valuePrototype
+}
+
+// Keys returns the keys of the map in ascending order
+func (d *Map) Keys() []string {
+	keys := []string{}
+	for key := range d.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Get returns the data handler of the value of the key
+func (d *Map) Get(key string) (Data, bool) {
+	value, ok := d.entries[key]
+	return value, ok
+}
+
+// validateKey validates the key with the key prototype, the key should stay
+// a string as IPLD maps only have string keys
+func (d *Map) validateKey(key string, isDecoding bool) error {
+	k := d.keyPrototype.Prototype()
+
+	var err error
+	if isDecoding {
+		_, err = k.Decode(key)
+	} else {
+		err = k.Set(key)
+	}
+	if err != nil {
+		return fmt.Errorf("(Key %q) %s", key, err.Error())
+	}
+
+	enc, err := k.Encode()
+	if err != nil {
+		return fmt.Errorf("(Key %q) %s", key, err.Error())
+	}
+
+	if enc != key {
+		return fmt.Errorf("(Key %q) the key is not encoded as itself", key)
+	}
+
+	return nil
+}
+
+// Set the value of data handler map, the entries of the previous value are
+// replaced
+func (d *Map) Set(obj interface{}) error {
+	// reflect.ValueOf(nil) is an invalid value instead of panicking
+	m := reflect.ValueOf(obj)
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("Map: a map with string keys is expected but '%T' is found", obj)
+	}
+
+	entries := map[string]Data{}
+	iter := m.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		if err := d.validateKey(key, false); err != nil {
+			return err
+		}
+
+		value := d.valuePrototype.Prototype()
+		if err := value.Set(iter.Value().Interface()); err != nil {
+			return fmt.Errorf("(Key %q) %s", key, err.Error())
+		}
+		entries[key] = value
+	}
+
+	d.entries = entries
+	d.Base.MarkDefined()
+	return nil
+}
+
+// Encode Map, the keys are sorted by the canonical encoding
+func (d *Map) Encode() (interface{}, error) {
+	res := map[string]interface{}{}
+	for key, value := range d.entries {
+		enc, err := value.Encode()
+		if err != nil {
+			return nil, fmt.Errorf("(Key %q) %s", key, err.Error())
+		}
+		res[key] = enc
+	}
+
+	return res, nil
+}
+
+// Decode Map
+func (d *Map) Decode(obj interface{}) (interface{}, error) {
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		return nil,
+			fmt.Errorf("Map: 'map[string]interface{}' is expected but '%T' is found", obj)
+	}
+
+	entries := map[string]Data{}
+	res := map[string]interface{}{}
+	for key, elem := range m {
+		if err := d.validateKey(key, true); err != nil {
+			return nil, err
+		}
+
+		value := d.valuePrototype.Prototype()
+		dec, err := value.Decode(elem)
+		if err != nil {
+			return nil, fmt.Errorf("(Key %q) %s", key, err.Error())
+		}
+
+		res[key] = dec
+		entries[key] = value
+	}
+
+	d.entries = entries
+	d.Base.MarkDefined()
+	return res, nil
+}
+
+// ToJSON prepares the data for MarshalJSON, the keys are in ascending order
+func (d *Map) ToJSON() (interface{}, error) {
+	om := ordered.NewOrderedMap()
+	for _, key := range d.Keys() {
+		value, err := d.entries[key].ToJSON()
+		if err != nil {
+			return nil, fmt.Errorf("(Key %q) %s", key, err.Error())
+		}
+		om.Set(key, value)
+	}
+
+	return om, nil
+}
+
+// Resolve resolves the value
+func (d *Map) Resolve(path []string) (interface{}, []string, error) {
+	if len(path) == 0 {
+		res := map[string]interface{}{}
+		for key, value := range d.entries {
+			v, _, err := value.Resolve(path)
+			if err != nil {
+				return nil, nil, fmt.Errorf("(Key %q) %s", key, err.Error())
+			}
+			res[key] = v
+		}
+		return res, nil, nil
+	}
+
+	first, rest := path[0], path[1:]
+	value, ok := d.entries[first]
+	if !ok {
+		return nil, nil, fmt.Errorf("key %q does not exist", first)
+	}
+
+	return value.Resolve(rest)
+}
+
+// Describe returns the descriptor of Map
+func (d *Map) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindMap, d.IsRequired())
+	desc.Keys = Describe(d.keyPrototype)
+	desc.Elem = Describe(d.valuePrototype)
+	return desc
+}
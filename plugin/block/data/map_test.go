@@ -0,0 +1,72 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func encodeMap(t *testing.T, d *Map) interface{} {
+	enc, err := d.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return enc
+}
+
+// Each Set replaces the entries, and a failed Set keeps them
+func TestMapSet(t *testing.T) {
+	d := NewDataMap("m", true, NewString("_", true), NewString("_", true))
+
+	if err := d.Set(map[string]interface{}{"a": "1", "b": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set(map[string]string{"c": "3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{"c": "3"}
+	if enc := encodeMap(t, d); !reflect.DeepEqual(enc, expected) {
+		t.Errorf("Set: %v is expected but %v is found", expected, enc)
+	}
+
+	if err := d.Set(map[string]interface{}{"d": "4", "e": 5}); err == nil {
+		t.Errorf("Set: error is expected for a value which is not a string")
+	}
+	if err := d.Set([]interface{}{"f"}); err == nil {
+		t.Errorf("Set: error is expected for an array")
+	}
+
+	if enc := encodeMap(t, d); !reflect.DeepEqual(enc, expected) {
+		t.Errorf("Set: %v is expected after failures but %v is found", expected, enc)
+	}
+
+	if _, err := d.Decode(map[string]interface{}{"g": "7"}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = map[string]interface{}{"g": "7"}
+	if enc := encodeMap(t, d); !reflect.DeepEqual(enc, expected) {
+		t.Errorf("Decode: %v is expected but %v is found", expected, enc)
+	}
+	if keys := d.Keys(); !reflect.DeepEqual(keys, []string{"g"}) {
+		t.Errorf("Keys: [g] is expected but %v is found", keys)
+	}
+}
+
+// The keys are validated by the key prototype and stay as they are
+func TestMapKeys(t *testing.T) {
+	d := NewDataMap("m", true, NewLanguageTag("_", true), NewString("_", true))
+
+	if err := d.Set(map[string]interface{}{"en": "a", "zh-Hant": "b"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"EN", "en_US", ""} {
+		if err := d.Set(map[string]interface{}{key: "a"}); err == nil {
+			t.Errorf("Set: error is expected for key %q", key)
+		}
+		if _, err := d.Decode(map[string]interface{}{key: "a"}); err == nil {
+			t.Errorf("Decode: error is expected for key %q", key)
+		}
+	}
+}
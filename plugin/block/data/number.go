@@ -28,12 +28,29 @@ const (
 	Uint64T
 )
 
-// Number is a data handler for the number
+// String returns the name of the number type
+func (t NumberType) String() string {
+	switch t {
+	case Int32T:
+		return "int32"
+	case Uint32T:
+		return "uint32"
+	case Int64T:
+		return "int64"
+	case Uint64T:
+		return "uint64"
+	}
+	return fmt.Sprintf("NumberType(%d)", int(t))
+}
+
+// Number is a data handler for the number, which is encoded as a varint
+// byte string or as a native CBOR integer
 type Number struct {
 	*Base
 
-	number []byte
-	typ    NumberType
+	number   []byte
+	typ      NumberType
+	isNative bool
 
 	i32 int32
 	u32 uint32
@@ -51,14 +68,30 @@ func NewNumber(key string, isRequired bool, typ NumberType) *Number {
 	}
 }
 
+// NewNativeNumber creates a number data handler encoding the number as a
+// native CBOR integer
+func NewNativeNumber(key string, isRequired bool, typ NumberType) *Number {
+	return &Number{
+		Base:     NewBase(key, isRequired),
+		typ:      typ,
+		isNative: true,
+	}
+}
+
 // Prototype creates a prototype Number
 func (d *Number) Prototype() Data {
 	return &Number{
-		Base: d.Base.Prototype(),
-		typ:  d.typ,
+		Base:     d.Base.Prototype(),
+		typ:      d.typ,
+		isNative: d.isNative,
 	}
 }
 
+// IsNative checks whether the number is encoded as a native CBOR integer
+func (d *Number) IsNative() bool {
+	return d.isNative
+}
+
 // GetType returns the type of the number
 func (d *Number) GetType() NumberType {
 	return d.typ
@@ -290,16 +323,57 @@ func (d *Number) Set(obj interface{}) error {
 
 // Encode Number
 func (d *Number) Encode() (interface{}, error) {
-	return d.number, nil
+	if !d.isNative {
+		return d.number, nil
+	}
+
+	switch d.GetType() {
+	case Int32T:
+		return int64(d.i32), nil
+	case Uint32T:
+		return uint64(d.u32), nil
+	case Int64T:
+		return d.i64, nil
+	case Uint64T:
+		return d.u64, nil
+	}
+
+	return nil, fmt.Errorf("Number: unexpected type %d", d.GetType())
 }
 
-// Decode Number
+// Decode Number, the legacy number only accepts the varint byte string,
+// while the native number accepts both the native CBOR integer and the
+// varint byte string of the older versions
 func (d *Number) Decode(obj interface{}) (interface{}, error) {
-	number, ok := obj.([]byte)
-	if !ok {
+	var number []byte
+	switch v := obj.(type) {
+	case []byte:
+		number = v
+	case uint64, int64:
+		if !d.isNative {
+			return nil,
+				fmt.Errorf("Unknown error during decoding number: "+
+					"'[]byte' is expected but '%T' is found",
+					obj,
+				)
+		}
+
+		// Native CBOR integer, the range is checked by Set
+		if err := d.Set(v); err != nil {
+			return nil, err
+		}
+
+		value, _, err := d.Resolve(nil)
+		return value, err
+	default:
+		expected := "'[]byte'"
+		if d.isNative {
+			expected = "'[]byte' or integer"
+		}
 		return nil,
 			fmt.Errorf("Unknown error during decoding number: "+
-				"'[]byte' is expected but '%T' is found",
+				"%s is expected but '%T' is found",
+				expected,
 				obj,
 			)
 	}
@@ -396,3 +470,11 @@ func (d *Number) Resolve(path []string) (interface{}, []string, error) {
 
 	return nil, nil, fmt.Errorf("Number: unknown error")
 }
+
+// Describe returns the descriptor of Number
+func (d *Number) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindNumber, d.IsRequired())
+	desc.NumberType = d.GetType().String()
+	desc.Native = d.isNative
+	return desc
+}
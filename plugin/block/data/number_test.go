@@ -0,0 +1,97 @@
+package data
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+// Numbers are decoded from the legacy varint byte strings, and the native
+// numbers from the native CBOR integers as well, with the range of the type
+// checked
+func TestNumberDecode(t *testing.T) {
+	varint := func(i uint64) []byte {
+		b := make([]byte, binary.MaxVarintLen64)
+		return b[:binary.PutUvarint(b, i)]
+	}
+
+	cases := []struct {
+		typ      NumberType
+		value    interface{}
+		legacy   interface{}
+		expected interface{}
+	}{
+		{Uint32T, varint(100), uint32(100), uint32(100)},
+		{Uint32T, uint64(100), nil, uint32(100)},
+		{Uint32T, varint(1 << 32), nil, nil},
+		{Uint32T, uint64(1 << 32), nil, nil},
+		{Uint32T, int64(-1), nil, nil},
+		{Uint32T, "100", nil, nil},
+		{Uint64T, varint(1 << 40), uint64(1 << 40), uint64(1 << 40)},
+		{Uint64T, uint64(1 << 40), nil, uint64(1 << 40)},
+		{Int32T, int64(-100), nil, int32(-100)},
+		{Int32T, int64(-1 << 32), nil, nil},
+		{Int64T, int64(-1 << 40), nil, int64(-1 << 40)},
+		{Int64T, 1.0, nil, nil},
+	}
+
+	for _, c := range cases {
+		for _, d := range []*Number{
+			NewNumber("n", true, c.typ),
+			NewNativeNumber("n", true, c.typ),
+		} {
+			expected := c.legacy
+			if d.IsNative() {
+				expected = c.expected
+			}
+
+			value, err := d.Decode(c.value)
+			if expected == nil {
+				if err == nil {
+					t.Errorf("Decode(%v) as %s (native %v): error is expected but %v is found", c.value, c.typ, d.IsNative(), value)
+				}
+				continue
+			}
+
+			if err != nil {
+				t.Errorf("Decode(%v) as %s (native %v): %s", c.value, c.typ, d.IsNative(), err)
+				continue
+			}
+
+			if value != expected {
+				t.Errorf("Decode(%v) as %s (native %v): %v is expected but %v is found", c.value, c.typ, d.IsNative(), expected, value)
+			}
+		}
+	}
+}
+
+// The native numbers are encoded as CBOR integers and the others as varint
+// byte strings
+func TestNumberEncode(t *testing.T) {
+	d := NewNumber("n", true, Uint32T)
+	if err := d.Set(100); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := d.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if b, ok := enc.([]byte); !ok || len(b) != 1 || b[0] != 100 {
+		t.Errorf("Encode: varint byte string is expected but %#v is found", enc)
+	}
+
+	native := NewNativeNumber("n", true, Int32T)
+	if err := native.Set(-100); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err = native.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if enc != int64(-100) {
+		t.Errorf("Encode: native integer is expected but %#v is found", enc)
+	}
+}
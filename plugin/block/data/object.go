@@ -14,6 +14,9 @@ import (
 type Codec interface {
 	MarkNested()
 
+	GetName() string
+	GetSchema() []Data
+
 	GetData() (*ordered.OrderedMap, error)
 	SetData(map[string]interface{}) error
 
@@ -60,6 +63,11 @@ func (d *Object) Prototype() Data {
 	}
 }
 
+// GetPrototypeFunc returns the factory function of the nested ISCN object
+func (d *Object) GetPrototypeFunc() ObjectPrototypeFunc {
+	return d.prototypeFunc
+}
+
 // Set the value of Object
 func (d *Object) Set(obj interface{}) error {
 	if value, ok := obj.(map[string]interface{}); ok {
@@ -108,3 +116,11 @@ func (d *Object) ToJSON() (interface{}, error) {
 func (d *Object) Resolve(path []string) (interface{}, []string, error) {
 	return d.object.Resolve(path)
 }
+
+// Describe returns the descriptor of Object
+func (d *Object) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindObject, d.IsRequired())
+	desc.Schema = d.object.GetName()
+	desc.Fields = DescribeSchema(d.object.GetSchema())
+	return desc
+}
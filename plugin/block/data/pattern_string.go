@@ -90,6 +90,13 @@ func (d *PatternString) Resolve(path []string) (interface{}, []string, error) {
 	return d.value.Resolve(path)
 }
 
+// Describe returns the descriptor of PatternString
+func (d *PatternString) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindPatternString, d.IsRequired())
+	desc.Pattern = d.pattern.String()
+	return desc
+}
+
 // ==================================================
 // Timestamp
 // ==================================================
@@ -127,35 +134,11 @@ func (d *Timestamp) Prototype() Data {
 	}
 }
 
-// ==================================================
-// LikeCoinChainID
-// ==================================================
-
-// LikeCoinChainID is a data handler for the LikeCoin chain ID
-type LikeCoinChainID struct {
-	*PatternString
-}
-
-var _ Data = (*LikeCoinChainID)(nil)
-
-// NewLikeCoinChainID creates a LikeCoin chain ID handler
-func NewLikeCoinChainID(key string, isRequired bool) *LikeCoinChainID {
-	base := NewPatternString(
-		key,
-		isRequired,
-		`lcc://id/cosmos1[02-9ac-hj-np-z]{38}$`,
-	)
-
-	return &LikeCoinChainID{
-		PatternString: base,
-	}
-}
-
-// Prototype creates a prototype LikeCoinChainID
-func (d *LikeCoinChainID) Prototype() Data {
-	return &LikeCoinChainID{
-		PatternString: NewPatternString(d.GetKey(), d.IsRequired(), d.pattern.String()),
-	}
+// Describe returns the descriptor of Timestamp
+func (d *Timestamp) Describe() *Descriptor {
+	desc := d.PatternString.Describe()
+	desc.Kind = KindTimestamp
+	return desc
 }
 
 // ==================================================
@@ -189,6 +172,13 @@ func (d *Hash) Prototype() Data {
 	}
 }
 
+// Describe returns the descriptor of Hash
+func (d *Hash) Describe() *Descriptor {
+	desc := d.PatternString.Describe()
+	desc.Kind = KindHash
+	return desc
+}
+
 // ==================================================
 // URL
 // ==================================================
@@ -271,3 +261,8 @@ func (d *URL) ToJSON() (interface{}, error) {
 func (d *URL) Resolve(path []string) (interface{}, []string, error) {
 	return d.value.Resolve(path)
 }
+
+// Describe returns the descriptor of URL
+func (d *URL) Describe() *Descriptor {
+	return NewDescriptor(d.GetKey(), KindURL, d.IsRequired())
+}
@@ -75,3 +75,8 @@ func (d *String) Resolve(path []string) (interface{}, []string, error) {
 
 	return d.value, nil, nil
 }
+
+// Describe returns the descriptor of String
+func (d *String) Describe() *Descriptor {
+	return NewDescriptor(d.GetKey(), KindString, d.IsRequired())
+}
@@ -0,0 +1,312 @@
+package data
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ipfs/go-cid"
+	"gitlab.com/c0b/go-ordered-json"
+)
+
+// ==================================================
+// Union
+// ==================================================
+
+// Discriminator checks whether the value being set or decoded belongs to a
+// member of a kinded union
+type Discriminator func(obj interface{}) bool
+
+// ByType selects the member by the Go types of the samples, e.g.
+// ByType(cid.Cid{}, []byte{}) for a CID which is set as cid.Cid and decoded
+// from a byte string
+func ByType(samples ...interface{}) Discriminator {
+	types := []reflect.Type{}
+	for _, sample := range samples {
+		types = append(types, reflect.TypeOf(sample))
+	}
+
+	return func(obj interface{}) bool {
+		typ := reflect.TypeOf(obj)
+		for _, t := range types {
+			if t == typ {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// CBOR major types of the values in the IPLD data model
+const (
+	MajorUint   byte = 0
+	MajorNegInt byte = 1
+	MajorBytes  byte = 2
+	MajorText   byte = 3
+	MajorArray  byte = 4
+	MajorMap    byte = 5
+	MajorTag    byte = 6
+	MajorSimple byte = 7
+)
+
+// ByMajorType selects the member by the CBOR major type of the value, links
+// are tags and booleans, floats and null are simple values
+func ByMajorType(majors ...byte) Discriminator {
+	return func(obj interface{}) bool {
+		major, ok := majorType(obj)
+		if !ok {
+			return false
+		}
+
+		for _, m := range majors {
+			if m == major {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// majorType returns the CBOR major type of the value in the IPLD data model
+func majorType(obj interface{}) (byte, bool) {
+	switch obj.(type) {
+	case nil, bool, float32, float64:
+		return MajorSimple, true
+	case cid.Cid:
+		return MajorTag, true
+	case []byte:
+		return MajorBytes, true
+	case string:
+		return MajorText, true
+	}
+
+	value := reflect.ValueOf(obj)
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() < 0 {
+			return MajorNegInt, true
+		}
+		return MajorUint, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return MajorUint, true
+	case reflect.Slice, reflect.Array:
+		return MajorArray, true
+	case reflect.Map:
+		return MajorMap, true
+	}
+
+	return 0, false
+}
+
+// UnionMember is a member of a union
+type UnionMember struct {
+	Prototype Data
+
+	// Match selects the member in a kinded union, the first matched member is
+	// used
+	Match Discriminator
+
+	// Tag is the key of the member in a keyed union, which is represented as
+	// a map with the tag as its only key, e.g. {"url": "https://..."}
+	Tag string
+}
+
+// Union is a data handler of one of the members, the member is selected by
+// the value in a kinded union or by the tag in a keyed union
+type Union struct {
+	*Base
+
+	members []UnionMember
+	isKeyed bool
+
+	index   int
+	handler Data
+}
+
+var _ Data = (*Union)(nil)
+
+// NewUnion creates a kinded union data handler, the member is selected by the
+// discriminators
+func NewUnion(key string, isRequired bool, members ...UnionMember) *Union {
+	return &Union{
+		Base:    NewBase(key, isRequired),
+		members: members,
+		index:   -1,
+	}
+}
+
+// NewKeyedUnion creates a keyed union data handler, the member is selected by
+// the tag
+func NewKeyedUnion(key string, isRequired bool, members ...UnionMember) *Union {
+	return &Union{
+		Base:    NewBase(key, isRequired),
+		members: members,
+		isKeyed: true,
+		index:   -1,
+	}
+}
+
+// Prototype creates a prototype Union
+func (d *Union) Prototype() Data {
+	return &Union{
+		Base:    d.Base.Prototype(),
+		members: d.members,
+		isKeyed: d.isKeyed,
+		index:   -1,
+	}
+}
+
+// IsKeyed checks whether the union is a keyed union
+func (d *Union) IsKeyed() bool {
+	return d.isKeyed
+}
+
+// GetMembers returns the members of the union
+func (d *Union) GetMembers() []UnionMember {
+	return d.members
+}
+
+// Selected returns the index and the data handler of the selected member, the
+// index is -1 if no member is selected
+func (d *Union) Selected() (int, Data) {
+	return d.index, d.handler
+}
+
+// selectMember selects the member of the value and returns the index, a new
+// data handler and the value of the member, the selection is only kept by
+// the caller once the value is accepted by the handler
+func (d *Union) selectMember(obj interface{}) (int, Data, interface{}, error) {
+	if d.isKeyed {
+		m, ok := obj.(map[string]interface{})
+		if !ok || len(m) != 1 {
+			return -1, nil, nil,
+				fmt.Errorf("Union: a map with a single tag is expected but '%T' is found", obj)
+		}
+
+		var tag string
+		for key := range m {
+			tag = key
+		}
+
+		for i, member := range d.members {
+			if member.Tag == tag {
+				return i, member.Prototype.Prototype(), m[tag], nil
+			}
+		}
+		return -1, nil, nil, fmt.Errorf("Union: unknown tag %q", tag)
+	}
+
+	for i, member := range d.members {
+		if member.Match != nil && member.Match(obj) {
+			return i, member.Prototype.Prototype(), obj, nil
+		}
+	}
+
+	return -1, nil, nil, fmt.Errorf("Union: '%T' does not match any member", obj)
+}
+
+// Set the value of Union, the selected member is kept if the value is not
+// accepted
+func (d *Union) Set(obj interface{}) error {
+	index, handler, value, err := d.selectMember(obj)
+	if err != nil {
+		return err
+	}
+
+	if err := handler.Set(value); err != nil {
+		return err
+	}
+
+	d.index, d.handler = index, handler
+	d.Base.MarkDefined()
+	return nil
+}
+
+// Encode Union
+func (d *Union) Encode() (interface{}, error) {
+	if d.handler == nil {
+		return nil, fmt.Errorf("Union: no member is selected")
+	}
+
+	enc, err := d.handler.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.isKeyed {
+		return map[string]interface{}{d.members[d.index].Tag: enc}, nil
+	}
+	return enc, nil
+}
+
+// Decode Union
+func (d *Union) Decode(obj interface{}) (interface{}, error) {
+	index, handler, value, err := d.selectMember(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	dec, err := handler.Decode(value)
+	if err != nil {
+		return nil, err
+	}
+
+	d.index, d.handler = index, handler
+	d.Base.MarkDefined()
+	return dec, nil
+}
+
+// ToJSON prepares the data for MarshalJSON
+func (d *Union) ToJSON() (interface{}, error) {
+	if d.handler == nil {
+		return nil, fmt.Errorf("Union: no member is selected")
+	}
+
+	value, err := d.handler.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	if d.isKeyed {
+		om := ordered.NewOrderedMap()
+		om.Set(d.members[d.index].Tag, value)
+		return om, nil
+	}
+	return value, nil
+}
+
+// Resolve resolves the value, the tag is a path element in a keyed union
+func (d *Union) Resolve(path []string) (interface{}, []string, error) {
+	if d.handler == nil {
+		return nil, nil, fmt.Errorf("Union: no member is selected")
+	}
+
+	if !d.isKeyed {
+		return d.handler.Resolve(path)
+	}
+
+	tag := d.members[d.index].Tag
+	if len(path) == 0 {
+		value, _, err := d.handler.Resolve(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return map[string]interface{}{tag: value}, nil, nil
+	}
+
+	if path[0] != tag {
+		return nil, nil, fmt.Errorf("no such link")
+	}
+	return d.handler.Resolve(path[1:])
+}
+
+// Describe returns the descriptor of Union
+func (d *Union) Describe() *Descriptor {
+	desc := NewDescriptor(d.GetKey(), KindUnion, d.IsRequired())
+	desc.Variants = []*Descriptor{}
+	for _, member := range d.members {
+		variant := Describe(member.Prototype)
+		variant.Tag = member.Tag
+		desc.Variants = append(desc.Variants, variant)
+	}
+	return desc
+}
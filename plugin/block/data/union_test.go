@@ -0,0 +1,149 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestUnion(isKeyed bool) *Union {
+	members := []UnionMember{
+		{
+			Prototype: NewURL("_", true),
+			Match:     ByMajorType(MajorText),
+			Tag:       "url",
+		},
+		{
+			Prototype: NewNativeNumber("_", true, Uint32T),
+			Match:     ByMajorType(MajorUint),
+			Tag:       "number",
+		},
+	}
+
+	if isKeyed {
+		return NewKeyedUnion("u", true, members...)
+	}
+	return NewUnion("u", true, members...)
+}
+
+func TestKindedUnion(t *testing.T) {
+	d := newTestUnion(false)
+	if index, handler := d.Selected(); index != -1 || handler != nil {
+		t.Errorf("Selected: no member is expected but %d is found", index)
+	}
+
+	if err := d.Set(uint64(1)); err != nil {
+		t.Fatal(err)
+	}
+	if index, _ := d.Selected(); index != 1 {
+		t.Errorf("Selected: 1 is expected but %d is found", index)
+	}
+
+	if err := d.Set("https://example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if index, _ := d.Selected(); index != 0 {
+		t.Errorf("Selected: 0 is expected but %d is found", index)
+	}
+
+	if err := d.Set(true); err == nil {
+		t.Errorf("Set(true): error is expected")
+	}
+}
+
+// A value which is not accepted by the selected member keeps the previous
+// selection and value
+func TestUnionFailedSet(t *testing.T) {
+	cases := []struct {
+		isKeyed bool
+		valid   interface{}
+		invalid []interface{}
+	}{
+		{
+			false,
+			"https://example.com",
+			[]interface{}{"not a url", uint64(1 << 32), true},
+		},
+		{
+			true,
+			map[string]interface{}{"url": "https://example.com"},
+			[]interface{}{
+				map[string]interface{}{"url": "not a url"},
+				map[string]interface{}{"number": uint64(1 << 32)},
+				map[string]interface{}{"unknown": uint64(1)},
+				map[string]interface{}{"url": "https://example.com", "number": uint64(1)},
+				"https://example.com",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		d := newTestUnion(c.isKeyed)
+		if err := d.Set(c.valid); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, obj := range c.invalid {
+			if err := d.Set(obj); err == nil {
+				t.Errorf("Set(%v): error is expected", obj)
+			}
+			if _, err := d.Decode(obj); err == nil {
+				t.Errorf("Decode(%v): error is expected", obj)
+			}
+
+			if index, _ := d.Selected(); index != 0 {
+				t.Errorf("Set(%v): member 0 is expected to be kept but %d is found", obj, index)
+			}
+
+			enc, err := d.Encode()
+			if err != nil {
+				t.Fatalf("Encode after Set(%v): %s", obj, err)
+			}
+			if !reflect.DeepEqual(enc, c.valid) {
+				t.Errorf("Encode after Set(%v): %v is expected but %v is found", obj, c.valid, enc)
+			}
+		}
+	}
+}
+
+// A failed Set of a new union leaves no member selected
+func TestUnionFailedFirstSet(t *testing.T) {
+	d := newTestUnion(true)
+	if err := d.Set(map[string]interface{}{"number": uint64(1 << 32)}); err == nil {
+		t.Fatal("Set: error is expected")
+	}
+
+	if index, handler := d.Selected(); index != -1 || handler != nil {
+		t.Errorf("Selected: no member is expected but %d is found", index)
+	}
+	if d.IsDefined() {
+		t.Errorf("Union is defined after a failed Set")
+	}
+	if _, err := d.Encode(); err == nil {
+		t.Errorf("Encode: error is expected")
+	}
+}
+
+func TestKeyedUnionRoundTrip(t *testing.T) {
+	d := newTestUnion(true)
+
+	obj := map[string]interface{}{"number": uint64(7)}
+	if _, err := d.Decode(obj); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := d.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(enc, obj) {
+		t.Errorf("Encode: %v is expected but %v is found", obj, enc)
+	}
+
+	value, _, err := d.Resolve([]string{"number"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != uint32(7) {
+		t.Errorf("Resolve: 7 is expected but %#v is found", value)
+	}
+}
@@ -0,0 +1,73 @@
+package block
+
+import (
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+)
+
+// ==================================================
+// Introspection
+// ==================================================
+
+// SchemaDescriptor describes a version of a registered ISCN schema
+type SchemaDescriptor struct {
+	Codec   uint64             `json:"codec"`
+	Schema  string             `json:"schema"`
+	Version uint64             `json:"version"`
+	Fields  []*data.Descriptor `json:"fields"`
+
+	// Extensions describes the registered extensions applying to the codec
+	Extensions []*ExtensionDescriptor `json:"extensions,omitempty"`
+}
+
+// Describe returns the descriptor of specific codec and version
+func (r *Registry) Describe(codec uint64, version uint64) (*SchemaDescriptor, error) {
+	obj, err := r.New(codec, version)
+	if err != nil {
+		return nil, err
+	}
+
+	desc := &SchemaDescriptor{
+		Codec:   codec,
+		Schema:  obj.GetName(),
+		Version: version,
+		Fields:  data.DescribeSchema(obj.GetSchema()),
+
+		Extensions: r.Extensions(codec),
+	}
+
+	// Fill in the schema names of the linked blocks
+	for _, field := range desc.Fields {
+		field.Walk(func(d *data.Descriptor) {
+			if d.Kind == data.KindCid && d.Codec != 0 {
+				d.Schema, _ = r.SchemaName(d.Codec)
+			}
+		})
+	}
+
+	return desc, nil
+}
+
+// DescribeVersions returns the descriptors of all versions of the codec
+func (r *Registry) DescribeVersions(codec uint64) ([]*SchemaDescriptor, error) {
+	versions, err := r.Versions(codec)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*SchemaDescriptor{}
+	for _, version := range versions {
+		desc, err := r.Describe(codec, version)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, desc)
+	}
+
+	return res, nil
+}
+
+// Describe returns the descriptor of specific codec and version in the
+// default registry
+func Describe(codec uint64, version uint64) (*SchemaDescriptor, error) {
+	return DefaultRegistry.Describe(codec, version)
+}
@@ -0,0 +1,237 @@
+package entity
+
+import (
+	"fmt"
+
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+	"github.com/likecoin/iscn-ipld/plugin/identifier"
+	"gitlab.com/c0b/go-ordered-json"
+)
+
+// ==================================================
+// Identifier
+// ==================================================
+
+// Identifier is a data handler for an identifier of the entity in one of the
+// registered identifier schemes, e.g. {"scheme": "orcid", "value": "..."}
+type Identifier struct {
+	*data.Base
+
+	scheme string
+	value  string
+}
+
+var _ data.Data = (*Identifier)(nil)
+
+// NewIdentifier creates an identifier data handler
+func NewIdentifier(key string, isRequired bool) *Identifier {
+	return &Identifier{
+		Base: data.NewBase(key, isRequired),
+	}
+}
+
+// Prototype creates a prototype Identifier
+func (d *Identifier) Prototype() data.Data {
+	return &Identifier{
+		Base: d.Base.Prototype(),
+	}
+}
+
+// GetScheme returns the name of the identifier scheme
+func (d *Identifier) GetScheme() string {
+	return d.scheme
+}
+
+// GetValue returns the normalized identifier
+func (d *Identifier) GetValue() string {
+	return d.value
+}
+
+// Set the value of Identifier
+func (d *Identifier) Set(obj interface{}) error {
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Identifier: 'map[string]interface{}' is expected but '%T' is found", obj)
+	}
+
+	if len(m) != 2 {
+		return fmt.Errorf("Identifier: only \"scheme\" and \"value\" are expected")
+	}
+
+	scheme, ok := m["scheme"].(string)
+	if !ok {
+		return fmt.Errorf("Identifier: 'string' is expected for \"scheme\" but '%T' is found", m["scheme"])
+	}
+
+	value, ok := m["value"].(string)
+	if !ok {
+		return fmt.Errorf("Identifier: 'string' is expected for \"value\" but '%T' is found", m["value"])
+	}
+
+	// Store the normalized form so that the same identifier always produces
+	// the same block
+	normalized, err := identifier.Normalize(scheme, value)
+	if err != nil {
+		return err
+	}
+
+	d.scheme = scheme
+	d.value = normalized
+	d.Base.MarkDefined()
+	return nil
+}
+
+// Encode Identifier
+func (d *Identifier) Encode() (interface{}, error) {
+	return map[string]interface{}{
+		"scheme": d.scheme,
+		"value":  d.value,
+	}, nil
+}
+
+// Decode Identifier
+func (d *Identifier) Decode(obj interface{}) (interface{}, error) {
+	if err := d.Set(obj); err != nil {
+		return nil, err
+	}
+
+	d.Base.MarkDefined()
+	return d.Encode()
+}
+
+// ToJSON prepares the data for MarshalJSON
+func (d *Identifier) ToJSON() (interface{}, error) {
+	om := ordered.NewOrderedMap()
+	om.Set("scheme", d.scheme)
+	om.Set("value", d.value)
+	return om, nil
+}
+
+// Resolve resolves the value
+func (d *Identifier) Resolve(path []string) (interface{}, []string, error) {
+	if len(path) == 0 {
+		value, err := d.Encode()
+		return value, nil, err
+	}
+
+	if len(path) > 1 {
+		return nil, nil, fmt.Errorf("Unexpected path elements past %s", path[1])
+	}
+
+	switch path[0] {
+	case "scheme":
+		return d.scheme, nil, nil
+	case "value":
+		return d.value, nil, nil
+	}
+
+	return nil, nil, fmt.Errorf("no such link")
+}
+
+// KindIdentifier is the kind of entity identifier handler
+const KindIdentifier data.Kind = "identifier"
+
+// Describe returns the descriptor of Identifier
+func (d *Identifier) Describe() *data.Descriptor {
+	desc := data.NewDescriptor(d.GetKey(), KindIdentifier, d.IsRequired())
+	desc.Enum = identifier.Schemes()
+	return desc
+}
+
+// ==================================================
+// DID
+// ==================================================
+
+// DID is a data handler for a W3C decentralized identifier of the entity,
+// e.g. "did:key:z6Mk...", which binds the entity to its verification keys
+type DID struct {
+	*data.Base
+
+	value *data.String
+	did   *did.DID
+}
+
+var _ data.Data = (*DID)(nil)
+
+// NewDID creates a DID data handler
+func NewDID(key string, isRequired bool) *DID {
+	return &DID{
+		Base:  data.NewBase(key, isRequired),
+		value: data.NewString("", false),
+	}
+}
+
+// Prototype creates a prototype DID
+func (d *DID) Prototype() data.Data {
+	return &DID{
+		Base:  d.Base.Prototype(),
+		value: data.NewString("", false),
+	}
+}
+
+// Get returns the DID
+func (d *DID) Get() string {
+	return d.value.Get()
+}
+
+// GetDID returns the parsed DID
+func (d *DID) GetDID() *did.DID {
+	return d.did
+}
+
+// Set the value of DID
+func (d *DID) Set(obj interface{}) error {
+	if err := d.value.Set(obj); err != nil {
+		return err
+	}
+
+	parsed, err := did.Parse(d.value.Get())
+	if err != nil {
+		return err
+	}
+
+	// The key of did:key is checked as it is resolved offline
+	if parsed.Method == did.MethodKey {
+		if _, err := did.ParseMultibaseKey(parsed.ID); err != nil {
+			return err
+		}
+	}
+
+	d.did = parsed
+	d.Base.MarkDefined()
+	return nil
+}
+
+// Encode DID
+func (d *DID) Encode() (interface{}, error) {
+	return d.value.Encode()
+}
+
+// Decode DID
+func (d *DID) Decode(obj interface{}) (interface{}, error) {
+	if err := d.Set(obj); err != nil {
+		return nil, err
+	}
+
+	d.Base.MarkDefined()
+	return d.value.Get(), nil
+}
+
+// ToJSON prepares the data for MarshalJSON
+func (d *DID) ToJSON() (interface{}, error) {
+	return d.value.ToJSON()
+}
+
+// Resolve resolves the value
+func (d *DID) Resolve(path []string) (interface{}, []string, error) {
+	return d.value.Resolve(path)
+}
+
+// KindDID is the kind of entity DID handler
+const KindDID data.Kind = "did"
+
+// Describe returns the descriptor of DID
+func (d *DID) Describe() *data.Descriptor {
+	return data.NewDescriptor(d.GetKey(), KindDID, d.IsRequired())
+}
@@ -1,6 +1,8 @@
 package entity
 
 import (
+	"fmt"
+
 	"github.com/likecoin/iscn-ipld/plugin/block"
 	"github.com/likecoin/iscn-ipld/plugin/block/data"
 )
@@ -10,13 +12,25 @@ const (
 	SchemaName = "entity"
 )
 
-// Register registers the schema of entity block
+// Register registers the schema of entity block to the default registry
 func Register() {
-	block.RegisterIscnObjectFactory(
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers the schema of entity block to the registry
+func RegisterTo(r *block.Registry) {
+	r.Register(
 		block.CodecEntity,
 		SchemaName,
 		newSchemaV1,
+		newSchemaV2,
+		newSchemaV3,
+		newSchemaV4,
 	)
+
+	r.RegisterMigration(block.CodecEntity, 1, migrateV1ToV2)
+	r.RegisterMigration(block.CodecEntity, 2, migrateV2ToV3)
+	r.RegisterMigration(block.CodecEntity, 3, migrateV3ToV4)
 }
 
 // ==================================================
@@ -71,3 +85,173 @@ func newSchemaV1() (block.Codec, error) {
 		base: entityBase,
 	}, nil
 }
+
+// ==================================================
+// schemaV2
+// ==================================================
+
+// schemaV2 represents an entity V2, the name and the description are
+// localized strings
+type schemaV2 struct {
+	*base
+}
+
+var _ block.IscnObject = (*schemaV2)(nil)
+
+func newSchemaV2() (block.Codec, error) {
+	schema := []data.Data{
+		data.NewLikeCoinChainID("id", true),
+		data.NewLocalizedString("name", false, data.UndeterminedLanguage),
+		data.NewLocalizedString("description", false, data.UndeterminedLanguage),
+	}
+
+	entityBase, err := newBase(2, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemaV2{
+		base: entityBase,
+	}, nil
+}
+
+// ==================================================
+// schemaV3
+// ==================================================
+
+// schemaV3 represents an entity V3, the entity can be identified by the
+// identifiers of the registered schemes besides the LikeCoin chain ID
+type schemaV3 struct {
+	*base
+
+	id          *data.LikeCoinChainID
+	identifiers *data.Array
+}
+
+var _ block.IscnObject = (*schemaV3)(nil)
+
+func newSchemaV3() (block.Codec, error) {
+	id := data.NewLikeCoinChainID("id", false)
+	identifiers := data.NewDataArray("identifiers", false, NewIdentifier("_", true))
+
+	schema := []data.Data{
+		id,
+		identifiers,
+		data.NewLocalizedString("name", false, data.UndeterminedLanguage),
+		data.NewLocalizedString("description", false, data.UndeterminedLanguage),
+	}
+
+	entityBase, err := newBase(3, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV3{
+		base:        entityBase,
+		id:          id,
+		identifiers: identifiers,
+	}
+	entityBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// Validate the data
+func (o *schemaV3) Validate() error {
+	if !o.id.IsDefined() && len(o.identifiers.Elems()) == 0 {
+		return fmt.Errorf("Entity: either \"id\" or \"identifiers\" is expected")
+	}
+
+	return validateIdentifiers(o.identifiers)
+}
+
+// validateIdentifiers checks that the identifiers are not duplicated
+func validateIdentifiers(identifiers *data.Array) error {
+	seen := map[string]bool{}
+	for i, elem := range identifiers.Elems() {
+		id := elem.(*Identifier)
+		key := id.GetScheme() + " " + id.GetValue()
+		if seen[key] {
+			return fmt.Errorf("(Index %d) Entity: identifier %q of %q is duplicated", i, id.GetValue(), id.GetScheme())
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
+
+// ==================================================
+// schemaV4
+// ==================================================
+
+// schemaV4 represents an entity V4, the entity can be bound to its
+// verification keys by a DID
+type schemaV4 struct {
+	*base
+
+	id          *data.LikeCoinChainID
+	did         *DID
+	identifiers *data.Array
+}
+
+var _ block.IscnObject = (*schemaV4)(nil)
+
+func newSchemaV4() (block.Codec, error) {
+	id := data.NewLikeCoinChainID("id", false)
+	did := NewDID("did", false)
+	identifiers := data.NewDataArray("identifiers", false, NewIdentifier("_", true))
+
+	schema := []data.Data{
+		id,
+		did,
+		identifiers,
+		data.NewLocalizedString("name", false, data.UndeterminedLanguage),
+		data.NewLocalizedString("description", false, data.UndeterminedLanguage),
+	}
+
+	entityBase, err := newBase(4, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV4{
+		base:        entityBase,
+		id:          id,
+		did:         did,
+		identifiers: identifiers,
+	}
+	entityBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// Validate the data
+func (o *schemaV4) Validate() error {
+	if !o.id.IsDefined() && !o.did.IsDefined() && len(o.identifiers.Elems()) == 0 {
+		return fmt.Errorf("Entity: one of \"id\", \"did\" and \"identifiers\" is expected")
+	}
+
+	return validateIdentifiers(o.identifiers)
+}
+
+// ==================================================
+// Migrations
+// ==================================================
+
+// migrateV1ToV2 migrates an entity V1 to V2, the name and the description
+// are carried over in the undetermined language
+func migrateV1ToV2(m map[string]interface{}) ([]string, error) {
+	return nil, block.LocalizeStrings(m, data.UndeterminedLanguage, "name", "description")
+}
+
+// migrateV2ToV3 migrates an entity V2 to V3, the LikeCoin chain ID is kept
+// as the id
+func migrateV2ToV3(m map[string]interface{}) ([]string, error) {
+	return nil, nil
+}
+
+// migrateV3ToV4 migrates an entity V3 to V4, the DID identifiers are kept
+// as identifiers
+func migrateV3ToV4(m map[string]interface{}) ([]string, error) {
+	return nil, nil
+}
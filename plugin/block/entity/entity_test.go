@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// The entities stored before the checksums of the addresses were verified
+// are still decoded, but their addresses are not trusted
+func TestDecodeInvalidChecksum(t *testing.T) {
+	r := block.NewRegistry()
+	RegisterTo(r)
+
+	// The checksum of "lcc://id/cosmos1ajvs6w5pr7znzmyfvxv7c5ry39lqlmkp68zggw"
+	// with the last character changed
+	id := "lcc://id/cosmos1ajvs6w5pr7znzmyfvxv7c5ry39lqlmkp68zggq"
+
+	raw, err := block.EncodeCanonical(map[string]interface{}{
+		"context": uint64(1),
+		"id":      id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := mh.Sum(raw, mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := r.Decode(raw, cid.NewCidV1(block.CodecEntity, h))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if value, err := obj.GetString("id"); err != nil || value != id {
+		t.Errorf("ID %q is expected but %q (%v) is found", id, value, err)
+	}
+
+	addresses, err := Addresses(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(addresses) != 0 {
+		t.Errorf("The address with an invalid checksum is trusted")
+	}
+
+	if _, err := r.Encode(block.CodecEntity, 1, map[string]interface{}{"id": id}); err == nil {
+		t.Errorf("ID %q with an invalid checksum is encoded", id)
+	}
+
+	// Other IDs are still rejected
+	raw, err = block.EncodeCanonical(map[string]interface{}{
+		"context": uint64(1),
+		"id":      "lcc://id/cosmos1invalid",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err = mh.Sum(raw, mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Decode(raw, cid.NewCidV1(block.CodecEntity, h)); err == nil {
+		t.Errorf("ID %q is decoded", "lcc://id/cosmos1invalid")
+	}
+}
@@ -0,0 +1,152 @@
+package entity
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+	"github.com/likecoin/iscn-ipld/plugin/identifier"
+)
+
+// ==================================================
+// Verification keys
+// ==================================================
+
+// DIDs returns the DIDs of the entity, i.e. its "did" and its identifiers
+// of the "did" scheme
+func DIDs(obj block.IscnObject) ([]string, error) {
+	codec, ok := obj.(block.Codec)
+	if !ok || obj.Cid().Type() != block.CodecEntity {
+		return nil, fmt.Errorf("Entity: %s is not an entity", obj)
+	}
+
+	res := []string{}
+	seen := map[string]bool{}
+	add := func(value string) {
+		if !seen[value] {
+			seen[value] = true
+			res = append(res, value)
+		}
+	}
+
+	for _, handler := range codec.GetSchema() {
+		if !handler.IsDefined() {
+			continue
+		}
+
+		switch d := handler.(type) {
+		case *DID:
+			add(d.Get())
+		case *data.Array:
+			for _, elem := range d.Elems() {
+				id, ok := elem.(*Identifier)
+				if ok && id.GetScheme() == identifier.SchemeDID {
+					add(id.GetValue())
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// VerificationKeys resolves the DIDs of the entity and returns the public
+// keys of their assertion methods
+func VerificationKeys(obj block.IscnObject, resolver did.Resolver) ([]*did.PublicKey, error) {
+	dids, err := DIDs(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*did.PublicKey{}
+	for _, id := range dids {
+		doc, err := resolver.Resolve(id)
+		if err != nil {
+			return nil, err
+		}
+
+		keys, err := doc.AssertionKeys()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, keys...)
+	}
+
+	return res, nil
+}
+
+// Addresses returns the raw addresses of the entity, i.e. the address of its
+// LikeCoin chain ID and its identifiers of the "likecoin" and "bech32"
+// schemes
+func Addresses(obj block.IscnObject) ([][]byte, error) {
+	codec, ok := obj.(block.Codec)
+	if !ok || obj.Cid().Type() != block.CodecEntity {
+		return nil, fmt.Errorf("Entity: %s is not an entity", obj)
+	}
+
+	res := [][]byte{}
+	for _, handler := range codec.GetSchema() {
+		if !handler.IsDefined() {
+			continue
+		}
+
+		switch d := handler.(type) {
+		case *data.LikeCoinChainID:
+			// The addresses with invalid checksums are not trusted
+			if address := d.GetAddress(); address != nil {
+				res = append(res, address)
+			}
+		case *data.Array:
+			for _, elem := range d.Elems() {
+				id, ok := elem.(*Identifier)
+				if !ok {
+					continue
+				}
+
+				switch id.GetScheme() {
+				case identifier.SchemeLikeCoin, identifier.SchemeBech32:
+					value := strings.TrimPrefix(id.GetValue(), identifier.LikeCoinPrefix)
+					_, address, err := identifier.DecodeAddress(value)
+					if err != nil {
+						return nil, err
+					}
+					res = append(res, address)
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// IsControlledBy checks whether the key belongs to the entity, i.e. one of
+// the addresses of the entity is the address of the key or the key is an
+// assertion key of one of the DIDs of the entity
+func IsControlledBy(obj block.IscnObject, key *did.PublicKey, resolver did.Resolver) (bool, error) {
+	addresses, err := Addresses(obj)
+	if err != nil {
+		return false, err
+	}
+
+	for _, address := range addresses {
+		if bytes.Equal(address, key.Address()) {
+			return true, nil
+		}
+	}
+
+	keys, err := VerificationKeys(obj, resolver)
+	if err != nil {
+		return false, err
+	}
+
+	for _, k := range keys {
+		if k.Equal(key) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,131 @@
+package block
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ==================================================
+// ValidationError
+// ==================================================
+
+// ValidationError is the error of setting or decoding invalid data into an
+// ISCN object
+type ValidationError struct {
+	Schema  string
+	Version uint64
+
+	// Key is the dotted path of the property which is invalid, it is empty
+	// when the error is reported by the validator of the whole object
+	Key string
+
+	Err error
+}
+
+func newValidationError(b *Base, key string, err error) error {
+	// Error from a nested object
+	if nested, ok := err.(*ValidationError); ok {
+		if nested.Key != "" {
+			key = key + "." + nested.Key
+		}
+		err = nested.Err
+	}
+
+	return &ValidationError{
+		Schema:  b.name,
+		Version: b.version,
+		Key:     key,
+		Err:     err,
+	}
+}
+
+// Error returns the message of the underlying error
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// String is a helper for output
+func (e *ValidationError) String() string {
+	if e.Key == "" {
+		return fmt.Sprintf("<%s (v%d)>: %s", e.Schema, e.Version, e.Err)
+	}
+	return fmt.Sprintf("<%s (v%d)> %q: %s", e.Schema, e.Version, e.Key, e.Err)
+}
+
+// ==================================================
+// Registry errors
+// ==================================================
+
+var (
+	// ErrUnknownCodec is the error of a codec which is not registered
+	ErrUnknownCodec = errors.New("Codec is not registered")
+
+	// ErrUnsupportedVersion is the error of a version which is not registered
+	// or older than the minimum supported version
+	ErrUnsupportedVersion = errors.New("Version is not supported")
+
+	// ErrDeprecatedVersion is the error of creating an ISCN object of a
+	// deprecated version
+	ErrDeprecatedVersion = errors.New("Version is deprecated")
+
+	// ErrInvalidContext is the error of a block without a valid context
+	ErrInvalidContext = errors.New("Invalid ISCN IPLD object")
+
+	// ErrDecoderPanic is the error of a panic in decoding a block, which is a
+	// bug of the decoder rather than of the block
+	ErrDecoderPanic = errors.New("Decoder panicked")
+)
+
+// CodecError is the error of a codec which is not registered
+type CodecError struct {
+	Codec uint64
+}
+
+// Error returns the message of the error
+func (e *CodecError) Error() string {
+	return fmt.Sprintf("Codec 0x%x is not registered", e.Codec)
+}
+
+// Unwrap returns ErrUnknownCodec
+func (e *CodecError) Unwrap() error {
+	return ErrUnknownCodec
+}
+
+// VersionError is the error of a version which cannot be used
+type VersionError struct {
+	Codec   uint64
+	Schema  string
+	Version uint64
+
+	// Minimum is the minimum supported version of the codec
+	Minimum uint64
+
+	// Err is ErrUnsupportedVersion or ErrDeprecatedVersion
+	Err error
+}
+
+// Error returns the message of the error
+func (e *VersionError) Error() string {
+	switch {
+	case e.Err == ErrDeprecatedVersion:
+		return fmt.Sprintf("<%s (v%d)> is deprecated", e.Schema, e.Version)
+	case e.Version != 0 && e.Version < e.Minimum:
+		return fmt.Sprintf(
+			"<%s (v%d)> is no longer supported, the minimum version is v%d",
+			e.Schema,
+			e.Version,
+			e.Minimum,
+		)
+	}
+	return fmt.Sprintf("<%s (v%d)> is not implemented", e.Schema, e.Version)
+}
+
+// Unwrap returns the underlying error
+func (e *VersionError) Unwrap() error {
+	return e.Err
+}
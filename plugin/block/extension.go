@@ -0,0 +1,233 @@
+package block
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+)
+
+// ==================================================
+// Extension
+// ==================================================
+
+// Extension is a registered schema of the custom properties with a prefix,
+// e.g. "x-license:". The custom properties with the prefix are validated by
+// the data handlers of the extension, and unknown properties with the prefix
+// are rejected
+type Extension struct {
+	// Prefix is the namespace of the custom properties of the extension
+	Prefix string
+
+	// Codecs are the codecs the extension applies to, empty means all codecs
+	Codecs []uint64
+
+	// Properties are the data handlers of the custom properties, their keys
+	// should start with the prefix. The required properties are only required
+	// when any property of the extension is present in an object
+	Properties []data.Data
+}
+
+// ExtensionDescriptor describes a registered extension for introspection
+type ExtensionDescriptor struct {
+	Prefix string             `json:"prefix"`
+	Fields []*data.Descriptor `json:"fields"`
+}
+
+// appliesTo checks whether the extension applies to the codec
+func (e *Extension) appliesTo(codec uint64) bool {
+	if len(e.Codecs) == 0 {
+		return true
+	}
+
+	for _, c := range e.Codecs {
+		if c == codec {
+			return true
+		}
+	}
+	return false
+}
+
+// property returns the data handler of the custom property
+func (e *Extension) property(key string) data.Data {
+	for _, handler := range e.Properties {
+		if handler.GetKey() == key {
+			return handler
+		}
+	}
+	return nil
+}
+
+// RegisterExtension registers the extension, the prefix should not overlap
+// with the prefixes of the registered extensions
+func (r *Registry) RegisterExtension(ext Extension) error {
+	if ext.Prefix == "" {
+		return fmt.Errorf("Prefix of the extension is required")
+	}
+
+	keys := map[string]struct{}{}
+	for _, handler := range ext.Properties {
+		key := handler.GetKey()
+		if !strings.HasPrefix(key, ext.Prefix) || key == ext.Prefix {
+			return fmt.Errorf("Property %q is not in the extension %q", key, ext.Prefix)
+		}
+
+		if _, ok := keys[key]; ok {
+			return fmt.Errorf("Property %q is defined more than once", key)
+		}
+		keys[key] = struct{}{}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, registered := range r.extensions {
+		if strings.HasPrefix(ext.Prefix, registered.Prefix) ||
+			strings.HasPrefix(registered.Prefix, ext.Prefix) {
+			return fmt.Errorf(
+				"Extension %q overlaps with the extension %q",
+				ext.Prefix,
+				registered.Prefix,
+			)
+		}
+	}
+
+	ext.Codecs = append([]uint64{}, ext.Codecs...)
+	ext.Properties = append([]data.Data{}, ext.Properties...)
+	r.extensions = append(r.extensions, &ext)
+
+	return nil
+}
+
+// Extensions returns the descriptors of the extensions applying to the codec
+func (r *Registry) Extensions(codec uint64) []*ExtensionDescriptor {
+	res := []*ExtensionDescriptor{}
+	for _, ext := range r.codecExtensions(codec) {
+		res = append(res, &ExtensionDescriptor{
+			Prefix: ext.Prefix,
+			Fields: data.DescribeSchema(ext.Properties),
+		})
+	}
+	return res
+}
+
+// codecExtensions returns the extensions applying to the codec
+func (r *Registry) codecExtensions(codec uint64) []*Extension {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	res := []*Extension{}
+	for _, ext := range r.extensions {
+		if ext.appliesTo(codec) {
+			res = append(res, ext)
+		}
+	}
+	return res
+}
+
+// checkCustom checks the custom properties of the encoded or decoded data of
+// the ISCN object and its nested objects against the custom property policy
+// and the extensions of the codec. The data being encoded is validated by
+// setting it to the data handlers and replaced in m by their encoded form, so
+// that it is stored as it will be decoded; the decoded data is validated by
+// decoding it
+func (r *Registry) checkCustom(
+	codec uint64,
+	obj Codec,
+	m map[string]interface{},
+	fields []*data.Descriptor,
+	isEncoding bool,
+) error {
+	policy := r.CustomPolicy(codec)
+	exts := r.codecExtensions(codec)
+	if policy.Mode == Permissive && len(exts) == 0 {
+		return nil
+	}
+
+	newError := func(key string, err error) error {
+		return &ValidationError{
+			Schema:  obj.GetName(),
+			Version: obj.GetVersion(),
+			Key:     key,
+			Err:     err,
+		}
+	}
+
+	return walkCustomKeys(m, fields, "", func(path string, m map[string]interface{}, keys []string) error {
+		present := map[*Extension]bool{}
+		for _, key := range keys {
+			ext := matchExtension(exts, key)
+			if ext == nil {
+				if !policy.Allows(key) {
+					return newError(join(path, key), policy.reject(key))
+				}
+				continue
+			}
+			present[ext] = true
+
+			handler := ext.property(key)
+			if handler == nil {
+				return newError(
+					join(path, key),
+					fmt.Errorf("Custom property %q is not defined by the extension %q", key, ext.Prefix),
+				)
+			}
+
+			value := m[key]
+			if value == nil {
+				if handler.IsRequired() {
+					return newError(join(path, key), fmt.Errorf("The property %q is required", key))
+				}
+				continue
+			}
+
+			if isEncoding {
+				h := handler.Prototype()
+				if err := h.Set(value); err != nil {
+					return newError(join(path, key), err)
+				}
+
+				enc, err := h.Encode()
+				if err != nil {
+					return newError(join(path, key), err)
+				}
+				m[key] = enc
+			} else if _, err := handler.Prototype().Decode(value); err != nil {
+				return newError(join(path, key), err)
+			}
+		}
+
+		// The required properties of the extensions in use
+		for _, ext := range exts {
+			if !present[ext] {
+				continue
+			}
+
+			for _, handler := range ext.Properties {
+				if _, ok := m[handler.GetKey()]; !ok && handler.IsRequired() {
+					return newError(
+						join(path, handler.GetKey()),
+						fmt.Errorf("The property %q is required", handler.GetKey()),
+					)
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// matchExtension returns the extension of the custom property
+func matchExtension(exts []*Extension, key string) *Extension {
+	for _, ext := range exts {
+		if strings.HasPrefix(key, ext.Prefix) {
+			return ext
+		}
+	}
+	return nil
+}
+
+// RegisterExtension registers the extension in the default registry
+func RegisterExtension(ext Extension) error {
+	return DefaultRegistry.RegisterExtension(ext)
+}
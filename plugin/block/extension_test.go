@@ -0,0 +1,84 @@
+package block_test
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+func newContent() map[string]interface{} {
+	return map[string]interface{}{
+		"version":     uint64(1),
+		"type":        "article",
+		"fingerprint": "hash://sha256/9564b85669d5e96ac969dd0161b8475bbced9e5999c6ec598da718a3045d6f2e",
+		"title":       "Title",
+	}
+}
+
+func TestExtensionRoundTrip(t *testing.T) {
+	r := block.NewRegistry()
+	content.RegisterTo(r)
+
+	err := r.RegisterExtension(block.Extension{
+		Prefix: "x-test:",
+		Properties: []data.Data{
+			data.NewCid("x-test:cid", false, 0),
+			data.NewLink("x-test:link", false, 0),
+			data.NewNativeNumber("x-test:number", false, data.Uint64T),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := mh.Sum([]byte("x"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cid.NewCidV1(cid.Raw, h)
+
+	m := newContent()
+	m["x-test:cid"] = c
+	m["x-test:link"] = c
+	m["x-test:number"] = 1
+
+	obj, err := r.Encode(block.CodecContent, 5, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := m["x-test:cid"].(cid.Cid); !ok {
+		t.Errorf("Encode: the data of the caller is changed to %T", m["x-test:cid"])
+	}
+
+	dec, err := r.Decode(obj.RawData(), obj.Cid())
+	if err != nil {
+		t.Fatalf("Decode: %s", err)
+	}
+
+	if !dec.Cid().Equals(obj.Cid()) {
+		t.Errorf("Decode: %s is expected but %s is found", obj.Cid(), dec.Cid())
+	}
+}
+
+func TestEncodeByteArray(t *testing.T) {
+	r := block.NewRegistry()
+	content.RegisterTo(r)
+
+	m := newContent()
+	m["x-hash"] = [4]byte{1, 2, 3, 4}
+
+	obj, err := r.Encode(block.CodecContent, 5, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Decode(obj.RawData(), obj.Cid()); err != nil {
+		t.Errorf("Decode: %s", err)
+	}
+}
@@ -0,0 +1,217 @@
+package block
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/ipfs/go-cid"
+
+	node "github.com/ipfs/go-ipld-format"
+)
+
+// ==================================================
+// IPLD data model
+// ==================================================
+
+// NormalizeCustom converts the custom data to the IPLD data model: maps are
+// map[string]interface{}, lists are []interface{}, non-negative integers are
+// uint64, negative integers are int64, floats are float64, links are cid.Cid,
+// and strings, bytes, booleans and nil are kept as is. Other values, e.g.
+// time.Time and structs, are rejected
+func NormalizeCustom(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, bool, string, []byte:
+		return v, nil
+	case int:
+		return normalizeInt(int64(v)), nil
+	case int8:
+		return normalizeInt(int64(v)), nil
+	case int16:
+		return normalizeInt(int64(v)), nil
+	case int32:
+		return normalizeInt(int64(v)), nil
+	case int64:
+		return normalizeInt(v), nil
+	case uint:
+		return uint64(v), nil
+	case uint8:
+		return uint64(v), nil
+	case uint16:
+		return uint64(v), nil
+	case uint32:
+		return uint64(v), nil
+	case uint64:
+		return v, nil
+	case float32:
+		return normalizeFloat(float64(v))
+	case float64:
+		return normalizeFloat(v)
+	case json.Number:
+		if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return normalizeInt(i), nil
+		}
+
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u, nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number %q", v)
+		}
+		return normalizeFloat(f)
+	case cid.Cid:
+		if !v.Defined() {
+			return nil, fmt.Errorf("Undefined CID is not a valid link")
+		}
+		return v, nil
+	case *cid.Cid:
+		if v == nil {
+			return nil, nil
+		}
+		return NormalizeCustom(*v)
+	case *node.Link:
+		if v == nil {
+			return nil, nil
+		}
+		return NormalizeCustom(v.Cid)
+	case map[string]interface{}:
+		res := map[string]interface{}{}
+		for key, elem := range v {
+			norm, err := NormalizeCustom(elem)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", key, err)
+			}
+			res[key] = norm
+		}
+		return res, nil
+	case []interface{}:
+		res := []interface{}{}
+		for i, elem := range v {
+			norm, err := NormalizeCustom(elem)
+			if err != nil {
+				return nil, fmt.Errorf("(Index %d) %s", i, err)
+			}
+			res = append(res, norm)
+		}
+		return res, nil
+	}
+
+	// Typed maps and lists
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("Map key should be a string but '%s' is found", rv.Type().Key())
+		}
+
+		res := map[string]interface{}{}
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			norm, err := NormalizeCustom(iter.Value().Interface())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s", key, err)
+			}
+			res[key] = norm
+		}
+		return res, nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Copy out as the arrays are not addressable and the slices
+			// should not be shared with the caller
+			res := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(res), rv)
+			return res, nil
+		}
+
+		res := []interface{}{}
+		for i := 0; i < rv.Len(); i++ {
+			norm, err := NormalizeCustom(rv.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("(Index %d) %s", i, err)
+			}
+			res = append(res, norm)
+		}
+		return res, nil
+	}
+
+	return nil, fmt.Errorf("'%T' is not in the IPLD data model", value)
+}
+
+func normalizeInt(i int64) interface{} {
+	if i >= 0 {
+		return uint64(i)
+	}
+	return i
+}
+
+func normalizeFloat(f float64) (interface{}, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("%v is not a valid float", f)
+	}
+	return f, nil
+}
+
+// customToJSON prepares the custom data in the IPLD data model for
+// MarshalJSON, links and bytes are presented in the DAG-JSON form
+func customToJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []byte:
+		return map[string]interface{}{
+			"/": map[string]string{
+				"bytes": base64.RawStdEncoding.EncodeToString(v),
+			},
+		}
+	case cid.Cid:
+		return map[string]string{"/": v.String()}
+	case map[string]interface{}:
+		res := map[string]interface{}{}
+		for key, elem := range v {
+			res[key] = customToJSON(elem)
+		}
+		return res
+	case []interface{}:
+		res := []interface{}{}
+		for _, elem := range v {
+			res = append(res, customToJSON(elem))
+		}
+		return res
+	}
+	return value
+}
+
+// cloneData copies the maps and lists of the data, the other values are
+// shared
+func cloneData(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			res[key] = cloneData(elem)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, elem := range v {
+			res[i] = cloneData(elem)
+		}
+		return res
+	}
+	return value
+}
+
+// sortedKeys returns the keys of the map in ascending order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,47 @@
+package block
+
+import (
+	"bytes"
+	"testing"
+)
+
+type namedBytes []byte
+
+func TestNormalizeCustomBytes(t *testing.T) {
+	expected := []byte{1, 2, 3, 4}
+	cases := []interface{}{
+		[4]byte{1, 2, 3, 4},
+		namedBytes{1, 2, 3, 4},
+		map[string][4]byte{"x": {1, 2, 3, 4}},
+	}
+
+	for _, value := range cases {
+		norm, err := NormalizeCustom(value)
+		if err != nil {
+			t.Errorf("NormalizeCustom(%#v): %s", value, err)
+			continue
+		}
+
+		if m, ok := norm.(map[string]interface{}); ok {
+			norm = m["x"]
+		}
+
+		b, ok := norm.([]byte)
+		if !ok || !bytes.Equal(b, expected) {
+			t.Errorf("NormalizeCustom(%#v): %v is expected but %#v is found", value, expected, norm)
+		}
+	}
+}
+
+func TestNormalizeCustomCopiesBytes(t *testing.T) {
+	value := namedBytes{1, 2, 3}
+	norm, err := NormalizeCustom(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value[0] = 9
+	if norm.([]byte)[0] != 1 {
+		t.Errorf("NormalizeCustom: the bytes are shared with the caller")
+	}
+}
@@ -2,6 +2,7 @@ package kernel
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/btcsuite/btcutil/base58"
 	"github.com/likecoin/iscn-ipld/plugin/block/data"
@@ -39,6 +40,20 @@ func (d *ID) GetID() string {
 	return fmt.Sprintf("1/%s", base58.Encode(d.id))
 }
 
+// ParseID parses the human readable ID back to the raw ID
+func ParseID(s string) ([]byte, error) {
+	if !strings.HasPrefix(s, "1/") {
+		return nil, fmt.Errorf("ID: %q is not in form of \"1/<base58>\"", s)
+	}
+
+	id := base58.Decode(s[2:])
+	if len(id) != 32 {
+		return nil, fmt.Errorf("ID: should length 32 but %d is found", len(id))
+	}
+
+	return id, nil
+}
+
 // Set the value of ID
 func (d *ID) Set(obj interface{}) error {
 	if id, ok := obj.([]byte); ok {
@@ -82,3 +97,11 @@ func (d *ID) Resolve(path []string) (interface{}, []string, error) {
 
 	return d.GetID(), nil, nil
 }
+
+// KindID is the kind of ISCN ID handler
+const KindID data.Kind = "iscnID"
+
+// Describe returns the descriptor of ID
+func (d *ID) Describe() *data.Descriptor {
+	return data.NewDescriptor(d.GetKey(), KindID, d.IsRequired())
+}
@@ -12,13 +12,23 @@ const (
 	SchemaName = "iscn"
 )
 
-// Register registers the schema of ISCN kernel block
+// Register registers the schema of ISCN kernel block to the default registry
 func Register() {
-	block.RegisterIscnObjectFactory(
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers the schema of ISCN kernel block to the registry
+func RegisterTo(r *block.Registry) {
+	r.Register(
 		block.CodecISCN,
 		SchemaName,
 		newSchemaV1,
+		newSchemaV2,
+		newSchemaV3,
 	)
+
+	r.RegisterMigration(block.CodecISCN, 1, migrateV1ToV2)
+	r.RegisterMigration(block.CodecISCN, 2, migrateV2ToV3)
 }
 
 // ==================================================
@@ -111,3 +121,118 @@ func newSchemaV1() (block.Codec, error) {
 func (o *schemaV1) Validate() error {
 	return data.ValidateParent(o.version, o.parent)
 }
+
+// ==================================================
+// schemaV2
+// ==================================================
+
+// schemaV2 represents an ISCN kernel V2, the CIDs are encoded as tag 42 links
+type schemaV2 struct {
+	*base
+
+	version *data.Number
+	parent  *data.Cid
+}
+
+var _ block.IscnObject = (*schemaV2)(nil)
+
+func newSchemaV2() (block.Codec, error) {
+	id := NewID()
+	version := data.NewNumber("version", true, data.Uint64T)
+	parent := data.NewLink("parent", false, block.CodecISCN)
+
+	schema := []data.Data{
+		id,
+		data.NewTimestamp("timestamp", true),
+		version,
+		parent,
+		data.NewLink("rights", true, block.CodecRights),
+		data.NewLink("stakeholders", true, block.CodecStakeholders),
+		data.NewLink("content", true, block.CodecContent),
+	}
+
+	iscnKernelBase, err := newBase(2, schema, id)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV2{
+		base:    iscnKernelBase,
+		version: version,
+		parent:  parent,
+	}
+	iscnKernelBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// Validate the data
+func (o *schemaV2) Validate() error {
+	return data.ValidateParent(o.version, o.parent)
+}
+
+// ==================================================
+// schemaV3
+// ==================================================
+
+// schemaV3 represents an ISCN kernel V3, the version is encoded as a native
+// CBOR integer
+type schemaV3 struct {
+	*base
+
+	version *data.Number
+	parent  *data.Cid
+}
+
+var _ block.IscnObject = (*schemaV3)(nil)
+
+func newSchemaV3() (block.Codec, error) {
+	id := NewID()
+	version := data.NewNativeNumber("version", true, data.Uint64T)
+	parent := data.NewLink("parent", false, block.CodecISCN)
+
+	schema := []data.Data{
+		id,
+		data.NewTimestamp("timestamp", true),
+		version,
+		parent,
+		data.NewLink("rights", true, block.CodecRights),
+		data.NewLink("stakeholders", true, block.CodecStakeholders),
+		data.NewLink("content", true, block.CodecContent),
+	}
+
+	iscnKernelBase, err := newBase(3, schema, id)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV3{
+		base:    iscnKernelBase,
+		version: version,
+		parent:  parent,
+	}
+	iscnKernelBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// Validate the data
+func (o *schemaV3) Validate() error {
+	return data.ValidateParent(o.version, o.parent)
+}
+
+// ==================================================
+// Migrations
+// ==================================================
+
+// migrateV1ToV2 migrates an ISCN kernel V1 to V2, the CIDs are converted to
+// links
+func migrateV1ToV2(m map[string]interface{}) ([]string, error) {
+	return nil, block.BytesToLinks(m, "parent", "rights", "stakeholders", "content")
+}
+
+// migrateV2ToV3 migrates an ISCN kernel V2 to V3, the varint version is
+// decoded by the native number handler as is
+func migrateV2ToV3(m map[string]interface{}) ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,288 @@
+package block
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+)
+
+// ==================================================
+// Limits
+// ==================================================
+
+// Limits are the resource limits of decoding untrusted blocks, a zero value
+// means unlimited
+type Limits struct {
+	// MaxBlockSize is the maximum size of a block in bytes
+	MaxBlockSize int
+
+	// MaxArrayLength is the maximum length of an array, e.g. rights,
+	// stakeholders and tags
+	MaxArrayLength int
+
+	// MaxStringLength is the maximum length of a text or byte string in bytes
+	MaxStringLength int
+
+	// MaxDepth is the maximum nesting depth of maps and arrays in a block,
+	// which bounds the nesting of custom data, it is never more than
+	// MaxNesting
+	MaxDepth int
+
+	// MaxCustomKeys is the maximum number of custom properties of an object
+	MaxCustomKeys int
+}
+
+// DefaultLimits are the limits of new registries
+var DefaultLimits = Limits{
+	MaxBlockSize:    1 << 20,
+	MaxArrayLength:  1 << 12,
+	MaxStringLength: 1 << 16,
+	MaxDepth:        16,
+	MaxCustomKeys:   64,
+}
+
+// MaxNesting is the nesting depth which is never exceeded, even if MaxDepth
+// is unlimited
+const MaxNesting = 256
+
+// ErrLimitExceeded is the error of a block exceeding the decode limits
+var ErrLimitExceeded = errors.New("Decode limit is exceeded")
+
+// LimitError is the error of a block exceeding one of the decode limits
+type LimitError struct {
+	// Limit is the name of the field of Limits
+	Limit string
+
+	Max    uint64
+	Actual uint64
+
+	// Key is the dotted path of the property if it is known
+	Key string
+}
+
+// Error returns the message of the error
+func (e *LimitError) Error() string {
+	if e.Key == "" {
+		return fmt.Sprintf("%s is exceeded: %d > %d", e.Limit, e.Actual, e.Max)
+	}
+	return fmt.Sprintf("%s is exceeded at %q: %d > %d", e.Limit, e.Key, e.Actual, e.Max)
+}
+
+// Unwrap returns ErrLimitExceeded
+func (e *LimitError) Unwrap() error {
+	return ErrLimitExceeded
+}
+
+// SetLimits sets the decode limits of the registry
+func (r *Registry) SetLimits(limits Limits) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.limits = limits
+}
+
+// Limits returns the decode limits of the registry
+func (r *Registry) Limits() Limits {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.limits
+}
+
+func exceeds(limit int, actual uint64) bool {
+	return limit > 0 && actual > uint64(limit)
+}
+
+// ==================================================
+// Scanner
+// ==================================================
+
+// scanner checks the structure of CBOR data against the limits before it is
+// decoded, so that the declared lengths cannot cause huge allocations
+type scanner struct {
+	raw    []byte
+	pos    int
+	limits Limits
+}
+
+// CBOR major types
+const (
+	cborUint = iota
+	cborNegInt
+	cborBytes
+	cborText
+	cborArray
+	cborMap
+	cborTag
+	cborSimple
+)
+
+// checkLimits checks the raw data of a block against the limits
+func checkLimits(raw []byte, limits Limits) error {
+	if exceeds(limits.MaxBlockSize, uint64(len(raw))) {
+		return &LimitError{
+			Limit:  "MaxBlockSize",
+			Max:    uint64(limits.MaxBlockSize),
+			Actual: uint64(len(raw)),
+		}
+	}
+
+	s := &scanner{raw: raw, limits: limits}
+	if err := s.scan(1); err != nil {
+		return err
+	}
+
+	if s.pos != len(raw) {
+		return fmt.Errorf("%w, unexpected data after the object", ErrInvalidContext)
+	}
+
+	return nil
+}
+
+func (s *scanner) remaining() uint64 {
+	return uint64(len(s.raw) - s.pos)
+}
+
+// header reads the major type and the argument of a data item
+func (s *scanner) header() (byte, uint64, error) {
+	if s.remaining() < 1 {
+		return 0, 0, fmt.Errorf("%w, unexpected end of data", ErrInvalidContext)
+	}
+
+	b := s.raw[s.pos]
+	s.pos++
+
+	major, info := b>>5, b&0x1f
+	var size uint64
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		// Indefinite lengths are not allowed in IPLD
+		return 0, 0, fmt.Errorf("%w, invalid CBOR header 0x%02x", ErrInvalidContext, b)
+	}
+
+	if s.remaining() < size {
+		return 0, 0, fmt.Errorf("%w, unexpected end of data", ErrInvalidContext)
+	}
+
+	buf := make([]byte, 8)
+	copy(buf[8-size:], s.raw[s.pos:s.pos+int(size)])
+	s.pos += int(size)
+
+	return major, binary.BigEndian.Uint64(buf), nil
+}
+
+func (s *scanner) scan(depth int) error {
+	major, arg, err := s.header()
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case cborUint, cborNegInt, cborSimple:
+		// Floats and simple values are in the argument
+		return nil
+	case cborBytes, cborText:
+		if exceeds(s.limits.MaxStringLength, arg) {
+			return &LimitError{
+				Limit:  "MaxStringLength",
+				Max:    uint64(s.limits.MaxStringLength),
+				Actual: arg,
+			}
+		}
+
+		if arg > s.remaining() {
+			return fmt.Errorf("%w, unexpected end of data", ErrInvalidContext)
+		}
+
+		// Strings of DAG-CBOR are UTF-8
+		if major == cborText && !utf8.Valid(s.raw[s.pos:s.pos+int(arg)]) {
+			return fmt.Errorf("%w, invalid UTF-8 string", ErrInvalidContext)
+		}
+
+		s.pos += int(arg)
+		return nil
+	}
+
+	// Tags count as nesting as well
+	maxDepth := s.limits.MaxDepth
+	if maxDepth <= 0 || maxDepth > MaxNesting {
+		maxDepth = MaxNesting
+	}
+	if exceeds(maxDepth, uint64(depth)) {
+		return &LimitError{
+			Limit:  "MaxDepth",
+			Max:    uint64(maxDepth),
+			Actual: uint64(depth),
+		}
+	}
+
+	if major == cborTag {
+		return s.scan(depth + 1)
+	}
+
+	items := arg
+	if major == cborArray {
+		if exceeds(s.limits.MaxArrayLength, arg) {
+			return &LimitError{
+				Limit:  "MaxArrayLength",
+				Max:    uint64(s.limits.MaxArrayLength),
+				Actual: arg,
+			}
+		}
+	} else {
+		items = arg * 2
+		if arg > s.remaining() {
+			return fmt.Errorf("%w, unexpected end of data", ErrInvalidContext)
+		}
+	}
+
+	// Every item takes at least one byte
+	if items > s.remaining() {
+		return fmt.Errorf("%w, unexpected end of data", ErrInvalidContext)
+	}
+
+	for i := uint64(0); i < items; i++ {
+		if err := s.scan(depth + 1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkCustomKeys checks the number of custom properties of the decoded
+// CBOR data and its nested objects against the limits
+func checkCustomKeys(
+	m map[string]interface{},
+	fields []*data.Descriptor,
+	limits Limits,
+) error {
+	if limits.MaxCustomKeys <= 0 {
+		return nil
+	}
+
+	return walkCustomKeys(m, fields, "", func(path string, _ map[string]interface{}, keys []string) error {
+		if exceeds(limits.MaxCustomKeys, uint64(len(keys))) {
+			return &LimitError{
+				Limit:  "MaxCustomKeys",
+				Max:    uint64(limits.MaxCustomKeys),
+				Actual: uint64(len(keys)),
+				Key:    path,
+			}
+		}
+		return nil
+	})
+}
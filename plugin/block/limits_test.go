@@ -0,0 +1,124 @@
+package block_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// contentCid returns a CID of a content block with arbitrary data, the data
+// is not checked against the CID
+func contentCid(t *testing.T) cid.Cid {
+	h, err := mh.Sum([]byte("content"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cid.NewCidV1(block.CodecContent, h)
+}
+
+// nested returns a CBOR array nested depth times
+func nested(depth int) []byte {
+	return append(bytes.Repeat([]byte{0x81}, depth), 0x00)
+}
+
+func TestLimits(t *testing.T) {
+	large := newContent()
+	large["title"] = strings.Repeat("a", 100)
+
+	tags := newContent()
+	tags["tags"] = []interface{}{"a", "b", "c"}
+
+	custom := newContent()
+	custom["x-a"] = "a"
+	custom["x-b"] = "b"
+	custom["x-c"] = "c"
+
+	deep := newContent()
+	deep["x-a"] = []interface{}{[]interface{}{[]interface{}{"a"}}}
+
+	cases := []struct {
+		name   string
+		limits block.Limits
+		m      map[string]interface{}
+		limit  string
+		max    uint64
+		actual uint64
+	}{
+		{"block size", block.Limits{MaxBlockSize: 64}, large, "MaxBlockSize", 64, 0},
+		{"string length", block.Limits{MaxStringLength: 64}, large, "MaxStringLength", 64, 100},
+		{"array length", block.Limits{MaxArrayLength: 2}, tags, "MaxArrayLength", 2, 3},
+		{"custom keys", block.Limits{MaxCustomKeys: 2}, custom, "MaxCustomKeys", 2, 3},
+		{"depth", block.Limits{MaxDepth: 3}, deep, "MaxDepth", 3, 4},
+	}
+
+	for _, c := range cases {
+		r := block.NewRegistry()
+		content.RegisterTo(r)
+
+		obj, err := r.Encode(block.CodecContent, 5, c.m)
+		if err != nil {
+			t.Fatalf("%s: %s", c.name, err)
+		}
+
+		if _, err := r.Decode(obj.RawData(), obj.Cid()); err != nil {
+			t.Fatalf("%s: Decode with the default limits: %s", c.name, err)
+		}
+
+		r.SetLimits(c.limits)
+		_, err = r.Decode(obj.RawData(), obj.Cid())
+		if !errors.Is(err, block.ErrLimitExceeded) {
+			t.Fatalf("%s: ErrLimitExceeded is expected but %v is found", c.name, err)
+		}
+
+		var limitErr *block.LimitError
+		if !errors.As(err, &limitErr) {
+			t.Fatalf("%s: LimitError is expected but %T is found", c.name, err)
+		}
+
+		if c.actual == 0 {
+			c.actual = uint64(len(obj.RawData()))
+		}
+		if limitErr.Limit != c.limit || limitErr.Max != c.max || limitErr.Actual != c.actual {
+			t.Errorf(
+				"%s: %s %d > %d is expected but %s %d > %d is found",
+				c.name,
+				c.limit, c.actual, c.max,
+				limitErr.Limit, limitErr.Actual, limitErr.Max,
+			)
+		}
+	}
+}
+
+func TestMaxNesting(t *testing.T) {
+	r := block.NewRegistry()
+	content.RegisterTo(r)
+
+	for _, limits := range []block.Limits{{}, {MaxDepth: 2 * block.MaxNesting}} {
+		r.SetLimits(limits)
+
+		// A block as deep as it is long must not exhaust the stack
+		_, err := r.Decode(nested(1<<20), contentCid(t))
+
+		var limitErr *block.LimitError
+		if !errors.As(err, &limitErr) {
+			t.Fatalf("MaxDepth %d: LimitError is expected but %v is found", limits.MaxDepth, err)
+		}
+
+		if limitErr.Limit != "MaxDepth" || limitErr.Max != block.MaxNesting {
+			t.Errorf(
+				"MaxDepth %d: MaxDepth %d is expected but %s %d is found",
+				limits.MaxDepth,
+				block.MaxNesting,
+				limitErr.Limit,
+				limitErr.Max,
+			)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package block
+
+import (
+	"github.com/ipfs/go-cid"
+)
+
+// ==================================================
+// Loader
+// ==================================================
+
+// Loader loads the ISCN object of a CID, it is used to follow the links
+// between ISCN objects
+type Loader interface {
+	Load(c cid.Cid) (IscnObject, error)
+}
+
+// LoaderFunc is an adapter to use an ordinary function as a Loader
+type LoaderFunc func(c cid.Cid) (IscnObject, error)
+
+var _ Loader = (LoaderFunc)(nil)
+
+// Load calls f(c)
+func (f LoaderFunc) Load(c cid.Cid) (IscnObject, error) {
+	return f(c)
+}
@@ -0,0 +1,243 @@
+package block
+
+import (
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+)
+
+// ==================================================
+// Migration
+// ==================================================
+
+// MigrationFunc upgrades the decoded CBOR data of a version to the next
+// registered version in place. It returns the descriptions of the
+// information which cannot be carried over, which is empty if the migration
+// is lossless
+type MigrationFunc func(m map[string]interface{}) ([]string, error)
+
+// MigrationReport reports the migration of an ISCN object
+type MigrationReport struct {
+	Codec  uint64
+	Source cid.Cid
+	From   uint64
+	To     uint64
+
+	// Losses describes the information lost during the migration, prefixed
+	// with the versions of the migration step
+	Losses []string
+}
+
+// IsLossy checks whether any information is lost during the migration
+func (r *MigrationReport) IsLossy() bool {
+	return len(r.Losses) > 0
+}
+
+// RegisterMigration registers the migration of the codec from a version to
+// the next registered version
+func (r *Registry) RegisterMigration(codec uint64, from uint64, fn MigrationFunc) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.migrations[codec]; !ok {
+		r.migrations[codec] = map[uint64]MigrationFunc{}
+	}
+	r.migrations[codec][from] = fn
+}
+
+// migration returns the migration of the codec from the version
+func (r *Registry) migration(codec uint64, from uint64, to uint64) (MigrationFunc, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	fn, ok := r.migrations[codec][from]
+	if !ok {
+		return nil, fmt.Errorf(
+			"<%s (v%d)> cannot be migrated to v%d",
+			r.names[codec],
+			from,
+			to,
+		)
+	}
+
+	return fn, nil
+}
+
+// Migrate upgrades the ISCN object to the target version by chaining the
+// migrations of each registered version in between
+func (r *Registry) Migrate(
+	obj IscnObject,
+	target uint64,
+) (IscnObject, *MigrationReport, error) {
+	codec := obj.Cid().Type()
+	report := &MigrationReport{
+		Codec:  codec,
+		Source: obj.Cid(),
+		From:   obj.GetVersion(),
+		To:     target,
+		Losses: []string{},
+	}
+
+	if target == obj.GetVersion() {
+		return obj, report, nil
+	}
+
+	if target < obj.GetVersion() {
+		return nil, nil, fmt.Errorf(
+			"<%s (v%d)> cannot be downgraded to v%d",
+			obj.GetName(),
+			obj.GetVersion(),
+			target,
+		)
+	}
+
+	// Migrate the decoded CBOR data so that the migrations can deal with the
+	// changes of representation
+	m := map[string]interface{}{}
+	if err := cbor.DecodeInto(obj.RawData(), &m); err != nil {
+		return nil, nil, err
+	}
+
+	versions, err := r.Versions(codec)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	from := obj.GetVersion()
+	for _, to := range versions {
+		if to <= from || to > target {
+			continue
+		}
+
+		fn, err := r.migration(codec, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		losses, err := fn(m)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"<%s (v%d)> migration to v%d failed: %s",
+				obj.GetName(),
+				from,
+				to,
+				err,
+			)
+		}
+
+		for _, loss := range losses {
+			report.Losses = append(report.Losses, fmt.Sprintf("v%d -> v%d: %s", from, to, loss))
+		}
+		from = to
+	}
+
+	if from != target {
+		return nil, nil, &VersionError{
+			Codec:   codec,
+			Schema:  obj.GetName(),
+			Version: target,
+			Minimum: r.MinimumVersion(codec),
+			Err:     ErrUnsupportedVersion,
+		}
+	}
+	m[data.ContextKey] = target
+
+	res, err := r.New(codec, target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := res.Decode(m); err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := res.Encode(); err != nil {
+		return nil, nil, err
+	}
+
+	return res, report, nil
+}
+
+// DecodeLatest decodes the raw IPLD data and migrates it to the latest
+// version of the codec
+func (r *Registry) DecodeLatest(
+	rawData []byte,
+	c cid.Cid,
+) (IscnObject, *MigrationReport, error) {
+	obj, err := r.Decode(rawData, c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	latest, err := r.LatestVersion(c.Type())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Newer deprecated versions are not downgraded
+	if obj.GetVersion() > latest {
+		latest = obj.GetVersion()
+	}
+
+	return r.Migrate(obj, latest)
+}
+
+// BytesToLinks converts the CIDs of the keys in the decoded CBOR data from
+// byte strings to tag 42 links, the missing keys and the values which are
+// not byte strings, e.g. URLs, are skipped
+func BytesToLinks(m map[string]interface{}, keys ...string) error {
+	for _, key := range keys {
+		b, ok := m[key].([]byte)
+		if !ok {
+			continue
+		}
+
+		_, c, err := cid.CidFromBytes(b)
+		if err != nil {
+			return fmt.Errorf("%s: %s", key, err)
+		}
+		m[key] = c
+	}
+
+	return nil
+}
+
+// LocalizeStrings converts the plain strings of the keys in the decoded CBOR
+// data to localized strings in the language, the missing keys are skipped
+func LocalizeStrings(m map[string]interface{}, language string, keys ...string) error {
+	for _, key := range keys {
+		value, ok := m[key]
+		if !ok {
+			continue
+		}
+
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: 'string' is expected but '%T' is found", key, value)
+		}
+		m[key] = map[string]interface{}{language: str}
+	}
+
+	return nil
+}
+
+// RegisterMigration registers the migration of the codec from a version to
+// the next registered version in the default registry
+func RegisterMigration(codec uint64, from uint64, fn MigrationFunc) {
+	DefaultRegistry.RegisterMigration(codec, from, fn)
+}
+
+// Migrate upgrades the ISCN object to the target version with the
+// migrations in the default registry
+func Migrate(obj IscnObject, target uint64) (IscnObject, *MigrationReport, error) {
+	return DefaultRegistry.Migrate(obj, target)
+}
+
+// DecodeLatest decodes the raw IPLD data and migrates it to the latest
+// version of the codec with the default registry
+func DecodeLatest(rawData []byte, c cid.Cid) (IscnObject, *MigrationReport, error) {
+	return DefaultRegistry.DecodeLatest(rawData, c)
+}
@@ -0,0 +1,502 @@
+package block
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+
+	blocks "github.com/ipfs/go-block-format"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	node "github.com/ipfs/go-ipld-format"
+)
+
+// ==================================================
+// Registry
+// ==================================================
+
+// Registry is a set of registered ISCN schemas, it is safe for concurrent use
+type Registry struct {
+	mutex    sync.RWMutex
+	names    map[uint64]string
+	versions map[uint64]map[uint64]*schemaVersion
+
+	// minVersions maps the codecs to their minimum supported versions
+	minVersions map[uint64]uint64
+
+	// migrations maps the codecs to the migrations from each version
+	migrations map[uint64]map[uint64]MigrationFunc
+
+	limits Limits
+
+	// customPolicy is the policy of custom properties, it can be overridden
+	// per codec by codecCustomPolicies
+	customPolicy        CustomPolicy
+	codecCustomPolicies map[uint64]CustomPolicy
+
+	// extensions are the schemas of the custom properties
+	extensions []*Extension
+}
+
+// schemaVersion is a registered version of a schema
+type schemaVersion struct {
+	factory    CodecFactoryFunc
+	deprecated bool
+}
+
+// VersionOption is an option of registering a version
+type VersionOption func(*schemaVersion)
+
+// Deprecated marks the version as deprecated, the ISCN objects of deprecated
+// versions can be decoded but not encoded
+func Deprecated() VersionOption {
+	return func(v *schemaVersion) {
+		v.deprecated = true
+	}
+}
+
+// DefaultRegistry is the registry used by the package level functions
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		names:       map[uint64]string{},
+		versions:    map[uint64]map[uint64]*schemaVersion{},
+		minVersions: map[uint64]uint64{},
+		migrations:  map[uint64]map[uint64]MigrationFunc{},
+		limits:      DefaultLimits,
+
+		customPolicy:        PermissivePolicy(),
+		codecCustomPolicies: map[uint64]CustomPolicy{},
+		extensions:          []*Extension{},
+	}
+}
+
+// Register registers the factory functions of the versions 1 to N of the
+// schema, the factory function of version N is at index N-1. Registering a
+// codec again replaces the previous versions
+func (r *Registry) Register(
+	codec uint64,
+	schemaName string,
+	factories ...CodecFactoryFunc,
+) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.names[codec] = schemaName
+	r.versions[codec] = map[uint64]*schemaVersion{}
+	for i, factory := range factories {
+		r.versions[codec][uint64(i+1)] = &schemaVersion{factory: factory}
+	}
+}
+
+// RegisterVersion registers the factory function of a version of the schema,
+// versions are not necessarily consecutive
+func (r *Registry) RegisterVersion(
+	codec uint64,
+	schemaName string,
+	version uint64,
+	factory CodecFactoryFunc,
+	options ...VersionOption,
+) error {
+	if version == 0 {
+		return fmt.Errorf("<%s (v0)> is not a valid version", schemaName)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if name, ok := r.names[codec]; ok && name != schemaName {
+		return fmt.Errorf("Codec 0x%x is registered as %q", codec, name)
+	}
+
+	if _, ok := r.versions[codec][version]; ok {
+		return fmt.Errorf("<%s (v%d)> is already registered", schemaName, version)
+	}
+
+	v := &schemaVersion{factory: factory}
+	for _, option := range options {
+		option(v)
+	}
+
+	r.names[codec] = schemaName
+	if _, ok := r.versions[codec]; !ok {
+		r.versions[codec] = map[uint64]*schemaVersion{}
+	}
+	r.versions[codec][version] = v
+
+	return nil
+}
+
+// Deprecate marks a registered version as deprecated
+func (r *Registry) Deprecate(codec uint64, version uint64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	v, err := r.lookup(codec, version)
+	if err != nil {
+		return err
+	}
+
+	v.deprecated = true
+	return nil
+}
+
+// SetMinimumVersion sets the minimum supported version of the codec, the
+// older versions can neither be encoded nor decoded
+func (r *Registry) SetMinimumVersion(codec uint64, version uint64) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.versions[codec]; !ok {
+		return &CodecError{Codec: codec}
+	}
+
+	r.minVersions[codec] = version
+	return nil
+}
+
+// MinimumVersion returns the minimum supported version of the codec
+func (r *Registry) MinimumVersion(codec uint64) uint64 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.minVersions[codec]
+}
+
+// IsDeprecated checks whether the version of the codec is deprecated
+func (r *Registry) IsDeprecated(codec uint64, version uint64) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	v, ok := r.versions[codec][version]
+	return ok && v.deprecated
+}
+
+// IsRegistered checks whether the codec is registered
+func (r *Registry) IsRegistered(codec uint64) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, ok := r.versions[codec]
+	return ok
+}
+
+// SchemaName returns the schema name of the codec
+func (r *Registry) SchemaName(codec uint64) (string, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	name, ok := r.names[codec]
+	return name, ok
+}
+
+// LookupCodec returns the codec of the schema name
+func (r *Registry) LookupCodec(schemaName string) (uint64, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for codec, name := range r.names {
+		if name == schemaName {
+			return codec, true
+		}
+	}
+
+	return 0, false
+}
+
+// Codecs returns all registered codecs in ascending order
+func (r *Registry) Codecs() []uint64 {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	codecs := []uint64{}
+	for codec := range r.versions {
+		codecs = append(codecs, codec)
+	}
+	sort.Slice(codecs, func(i, j int) bool { return codecs[i] < codecs[j] })
+
+	return codecs
+}
+
+// Versions returns all supported versions of the codec in ascending order,
+// including the deprecated versions
+func (r *Registry) Versions(codec uint64) ([]uint64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.supportedVersions(codec)
+}
+
+func (r *Registry) supportedVersions(codec uint64) ([]uint64, error) {
+	schemas, ok := r.versions[codec]
+	if !ok {
+		return nil, &CodecError{Codec: codec}
+	}
+
+	versions := []uint64{}
+	for version := range schemas {
+		if version >= r.minVersions[codec] {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+
+	return versions, nil
+}
+
+// LatestVersion returns the latest supported version of the codec which is
+// not deprecated
+func (r *Registry) LatestVersion(codec uint64) (uint64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	versions, err := r.supportedVersions(codec)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := len(versions) - 1; i >= 0; i-- {
+		if !r.versions[codec][versions[i]].deprecated {
+			return versions[i], nil
+		}
+	}
+
+	return 0, &VersionError{
+		Codec:   codec,
+		Schema:  r.names[codec],
+		Minimum: r.minVersions[codec],
+		Err:     ErrUnsupportedVersion,
+	}
+}
+
+// lookup returns the registered version, the caller should hold the lock
+func (r *Registry) lookup(codec uint64, version uint64) (*schemaVersion, error) {
+	schemas, ok := r.versions[codec]
+	if !ok {
+		return nil, &CodecError{Codec: codec}
+	}
+
+	v, ok := schemas[version]
+	if !ok || version < r.minVersions[codec] {
+		return nil, &VersionError{
+			Codec:   codec,
+			Schema:  r.names[codec],
+			Version: version,
+			Minimum: r.minVersions[codec],
+			Err:     ErrUnsupportedVersion,
+		}
+	}
+
+	return v, nil
+}
+
+// factory returns the factory function of specific codec and version, the
+// deprecated versions are rejected for encoding
+func (r *Registry) factory(
+	codec uint64,
+	version uint64,
+	isEncoding bool,
+) (CodecFactoryFunc, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	v, err := r.lookup(codec, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if isEncoding && v.deprecated {
+		return nil, &VersionError{
+			Codec:   codec,
+			Schema:  r.names[codec],
+			Version: version,
+			Minimum: r.minVersions[codec],
+			Err:     ErrDeprecatedVersion,
+		}
+	}
+
+	return v.factory, nil
+}
+
+// New creates an empty ISCN object of specific codec and version
+func (r *Registry) New(codec uint64, version uint64) (Codec, error) {
+	newCodec, err := r.factory(codec, version, false)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := newCodec()
+	if err != nil {
+		return nil, err
+	}
+
+	r.bindCustomCheck(codec, obj)
+	return obj, nil
+}
+
+// customChecker is implemented by the objects embedding Base
+type customChecker interface {
+	setCustomCheck(func(map[string]interface{}) (map[string]interface{}, error))
+}
+
+// bindCustomCheck makes SetData of the object check the custom properties
+// against the custom property policy and the extensions of the registry
+func (r *Registry) bindCustomCheck(codec uint64, obj Codec) {
+	checker, ok := obj.(customChecker)
+	if !ok {
+		return
+	}
+
+	checker.setCustomCheck(func(m map[string]interface{}) (map[string]interface{}, error) {
+		// The custom properties of the extensions are replaced by their
+		// encoded form, which should not change the data of the caller
+		m = cloneData(m).(map[string]interface{})
+
+		fields := data.DescribeSchema(obj.GetSchema())
+		if err := r.checkCustom(codec, obj, m, fields, true); err != nil {
+			return nil, err
+		}
+		return m, nil
+	})
+}
+
+// Encode the data to specific ISCN object and version, the object should
+// follow the custom property policy and the extensions, and be within the
+// limits of the registry
+func (r *Registry) Encode(
+	codec uint64,
+	version uint64,
+	m map[string]interface{},
+) (IscnObject, error) {
+	newCodec, err := r.factory(codec, version, true)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := newCodec()
+	if err != nil {
+		return nil, err
+	}
+
+	r.bindCustomCheck(codec, obj)
+	if err := obj.SetData(m); err != nil {
+		return nil, err
+	}
+
+	enc, err := obj.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	// Reject the objects which cannot be decoded by the registry
+	fields := data.DescribeSchema(obj.GetSchema())
+	limits := r.Limits()
+	if err := checkLimits(obj.RawData(), limits); err != nil {
+		return nil, err
+	}
+
+	if err := checkCustomKeys(enc, fields, limits); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// DecodeBlock decodes the raw IPLD data back to data object
+func (r *Registry) DecodeBlock(block blocks.Block) (node.Node, error) {
+	return r.Decode(block.RawData(), block.Cid())
+}
+
+// logPanicOnce logs the first panic of the decoder
+var logPanicOnce sync.Once
+
+// Decode decodes the raw IPLD data back to data object, the data is
+// untrusted and checked against the limits, the canonical encoding, the
+// custom property policy and the extensions of the registry, any failure is
+// reported as an error
+func (r *Registry) Decode(rawData []byte, c cid.Cid) (obj IscnObject, err error) {
+	// The last resort against the bugs of the decoder, the stack is logged
+	// once as the blocks panicking the decoder may be crafted to flood the log
+	defer func() {
+		if p := recover(); p != nil {
+			logPanicOnce.Do(func() {
+				log.Printf("Decoding %s panicked: %v\n%s", c, p, debug.Stack())
+			})
+			obj, err = nil, fmt.Errorf("%w: %v", ErrDecoderPanic, p)
+		}
+	}()
+
+	limits := r.Limits()
+	if err := checkLimits(rawData, limits); err != nil {
+		return nil, err
+	}
+
+	rawObj := map[string]interface{}{}
+	if err := cbor.DecodeInto(rawData, &rawObj); err != nil {
+		return nil, err
+	}
+
+	if err := checkCanonical(rawData, rawObj); err != nil {
+		return nil, err
+	}
+
+	v, ok := rawObj[data.ContextKey]
+	if !ok {
+		return nil, fmt.Errorf("%w, missing context", ErrInvalidContext)
+	}
+
+	version, ok := v.(uint64)
+	if !ok {
+		return nil, fmt.Errorf(
+			"%w, context: 'uint64' is expected but '%T' is found",
+			ErrInvalidContext,
+			v,
+		)
+	}
+
+	res, err := r.New(c.Type(), version)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := data.DescribeSchema(res.GetSchema())
+	if err := checkCustomKeys(rawObj, fields, limits); err != nil {
+		return nil, err
+	}
+
+	if err := r.checkCustom(c.Type(), res, rawObj, fields, false); err != nil {
+		return nil, err
+	}
+
+	if err := res.Decode(rawObj); err != nil {
+		return nil, err
+	}
+
+	// Encode one more time to retrieve CID
+	if _, err := res.Encode(); err != nil {
+		return nil, err
+	}
+
+	// Verify the CID
+	if !res.Cid().Equals(c) {
+		current, err := res.Cid().StringOfBase('z')
+		if err != nil {
+			return nil, fmt.Errorf("Cannot retrieve current CID")
+		}
+
+		expected, err := c.StringOfBase('z')
+		if err != nil {
+			return nil, fmt.Errorf("Cannot retrieve expected CID")
+		}
+
+		return nil, fmt.Errorf("Cid %q is not matched: expected %q", current, expected)
+	}
+
+	return res, nil
+}
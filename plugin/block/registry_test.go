@@ -0,0 +1,63 @@
+package block
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// panicking is an ISCN object whose decoder has a bug
+type panicking struct {
+	*Base
+}
+
+func (p *panicking) Decode(map[string]interface{}) error {
+	panic("decoder bug")
+}
+
+func TestDecodePanic(t *testing.T) {
+	const codec = CodecTimePeriod
+
+	r := NewRegistry()
+	r.Register(codec, "time_period", func() (Codec, error) {
+		base, err := NewBase(codec, "time_period", 1, []data.Data{})
+		if err != nil {
+			return nil, err
+		}
+		return &panicking{Base: base}, nil
+	})
+
+	raw, err := EncodeCanonical(map[string]interface{}{"context": uint64(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := mh.Sum(raw, mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cid.NewCidV1(codec, h)
+
+	buf := &bytes.Buffer{}
+	log.SetOutput(buf)
+	defer log.SetOutput(os.Stderr)
+
+	for i := 0; i < 3; i++ {
+		_, err := r.Decode(raw, c)
+		if !errors.Is(err, ErrDecoderPanic) || !strings.Contains(err.Error(), "decoder bug") {
+			t.Errorf("Decode: ErrDecoderPanic is expected but %v is found", err)
+		}
+	}
+
+	if n := strings.Count(buf.String(), "panicked"); n != 1 {
+		t.Errorf("The stack should be logged once but %d is found", n)
+	}
+}
@@ -11,13 +11,21 @@ const (
 	SchemaName = "right"
 )
 
-// Register registers the schema of right block
+// Register registers the schema of right block to the default registry
 func Register() {
-	block.RegisterIscnObjectFactory(
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers the schema of right block to the registry
+func RegisterTo(r *block.Registry) {
+	r.Register(
 		block.CodecRight,
 		SchemaName,
 		newSchemaV1,
+		newSchemaV2,
 	)
+
+	r.RegisterMigration(block.CodecRight, 1, MigrateV1ToV2)
 }
 
 // ==================================================
@@ -80,3 +88,48 @@ func SchemaV1Prototype() data.Codec {
 	res, _ := newSchemaV1()
 	return res
 }
+
+// ==================================================
+// schemaV2
+// ==================================================
+
+// schemaV2 represents a right V2, the CIDs are encoded as tag 42 links
+type schemaV2 struct {
+	*base
+}
+
+var _ block.IscnObject = (*schemaV2)(nil)
+
+func newSchemaV2() (block.Codec, error) {
+	schema := []data.Data{
+		data.NewLink("holder", true, block.CodecEntity),
+		data.NewString("type", true),
+		data.NewLink("terms", true, 0),
+		data.NewObject("period", false, timeperiod.SchemaV1Prototype),
+		data.NewString("territory", false),
+	}
+
+	rightBase, err := newBase(2, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemaV2{
+		base: rightBase,
+	}, nil
+}
+
+// SchemaV2Prototype creates a prototype for schemaV2
+func SchemaV2Prototype() data.Codec {
+	res, _ := newSchemaV2()
+	return res
+}
+
+// ==================================================
+// Migrations
+// ==================================================
+
+// MigrateV1ToV2 migrates a right V1 to V2, the CIDs are converted to links
+func MigrateV1ToV2(m map[string]interface{}) ([]string, error) {
+	return nil, block.BytesToLinks(m, "holder", "terms")
+}
@@ -1,6 +1,8 @@
 package rights
 
 import (
+	"fmt"
+
 	"github.com/likecoin/iscn-ipld/plugin/block"
 	"github.com/likecoin/iscn-ipld/plugin/block/data"
 	"github.com/likecoin/iscn-ipld/plugin/block/right"
@@ -11,13 +13,21 @@ const (
 	SchemaName = "rights"
 )
 
-// Register registers the schema of rights block
+// Register registers the schema of rights block to the default registry
 func Register() {
-	block.RegisterIscnObjectFactory(
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers the schema of rights block to the registry
+func RegisterTo(r *block.Registry) {
+	r.Register(
 		block.CodecRights,
 		SchemaName,
 		newSchemaV1,
+		newSchemaV2,
 	)
+
+	r.RegisterMigration(block.CodecRights, 1, migrateV1ToV2)
 }
 
 // ==================================================
@@ -72,3 +82,56 @@ func newSchemaV1() (block.Codec, error) {
 		base: rightsBase,
 	}, nil
 }
+
+// ==================================================
+// schemaV2
+// ==================================================
+
+// schemaV2 represents a rights V2, the rights are right V2
+type schemaV2 struct {
+	*base
+}
+
+var _ block.IscnObject = (*schemaV2)(nil)
+
+func newSchemaV2() (block.Codec, error) {
+	prototype := data.NewObject("_", true, right.SchemaV2Prototype)
+
+	schema := []data.Data{
+		data.NewDataArray("rights", true, prototype),
+	}
+
+	rightsBase, err := newBase(2, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemaV2{
+		base: rightsBase,
+	}, nil
+}
+
+// ==================================================
+// Migrations
+// ==================================================
+
+// migrateV1ToV2 migrates a rights V1 to V2 by migrating each right
+func migrateV1ToV2(m map[string]interface{}) ([]string, error) {
+	rights, ok := m["rights"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Rights: '[]interface{}' is expected but '%T' is found", m["rights"])
+	}
+
+	for i, value := range rights {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("(Index %d) 'map[string]interface{}' is expected but '%T' is found", i, value)
+		}
+
+		if _, err := right.MigrateV1ToV2(obj); err != nil {
+			return nil, fmt.Errorf("(Index %d) %s", i, err)
+		}
+	}
+
+	return nil, nil
+}
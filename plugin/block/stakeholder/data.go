@@ -1,8 +1,6 @@
 package stakeholder
 
 import (
-	"fmt"
-
 	"github.com/ipfs/go-cid"
 	"github.com/likecoin/iscn-ipld/plugin/block"
 	"github.com/likecoin/iscn-ipld/plugin/block/data"
@@ -55,11 +53,10 @@ func (d *Type) Prototype() data.Data {
 // Footprint
 // ==================================================
 
-// Footprint is a data handler for the footprint link to the underlying work
+// Footprint is a data handler for the footprint link to the underlying work,
+// which is a union of an ISCN kernel CID and a URL
 type Footprint struct {
-	*data.Base
-
-	handler data.Data
+	*data.Union
 }
 
 var _ data.Data = (*Footprint)(nil)
@@ -67,75 +64,43 @@ var _ data.Data = (*Footprint)(nil)
 // NewFootprint creates a footprint data handler
 func NewFootprint() *Footprint {
 	return &Footprint{
-		Base: data.NewBase("footprint", false),
+		Union: data.NewUnion(
+			"footprint",
+			false,
+			data.UnionMember{
+				Prototype: data.NewCid("footprint", false, block.CodecISCN),
+				Match:     data.ByType(cid.Cid{}, []byte{}),
+			},
+			data.UnionMember{
+				Prototype: data.NewURL("footprint", false),
+				Match:     data.ByType(""),
+			},
+		),
 	}
 }
 
-// Prototype creates a protype Footprint
-func (d *Footprint) Prototype() data.Data {
+// NewLinkFootprint creates a footprint data handler encoding the CID as a
+// tag 42 link
+func NewLinkFootprint() *Footprint {
 	return &Footprint{
-		Base: d.Base.Prototype(),
-	}
-}
-
-// Set the value of link of footprint
-func (d *Footprint) Set(obj interface{}) error {
-	if d.handler != nil {
-		return fmt.Errorf("Footprint: re-create handler")
-	}
-
-	switch obj.(type) {
-	case cid.Cid:
-		d.handler = data.NewCid(d.GetKey(), d.IsRequired(), block.CodecISCN)
-	case string:
-		d.handler = data.NewURL(d.GetKey(), d.IsRequired())
-	default:
-		return fmt.Errorf("Footprint: link is expected but '%T' is found", obj)
-	}
-
-	if err := d.handler.Set(obj); err != nil {
-		return err
+		Union: data.NewUnion(
+			"footprint",
+			false,
+			data.UnionMember{
+				Prototype: data.NewLink("footprint", false, block.CodecISCN),
+				Match:     data.ByType(cid.Cid{}),
+			},
+			data.UnionMember{
+				Prototype: data.NewURL("footprint", false),
+				Match:     data.ByType(""),
+			},
+		),
 	}
-
-	d.Base.MarkDefined()
-	return nil
-}
-
-// Encode Footprint
-func (d *Footprint) Encode() (interface{}, error) {
-	return d.handler.Encode()
 }
 
-// Decode Footprint
-func (d *Footprint) Decode(obj interface{}) (interface{}, error) {
-	if d.handler != nil {
-		return nil, fmt.Errorf("Footprint: re-create handler")
-	}
-
-	switch obj.(type) {
-	case []uint8:
-		d.handler = data.NewCid(d.GetKey(), d.IsRequired(), block.CodecISCN)
-	case string:
-		d.handler = data.NewURL(d.GetKey(), d.IsRequired())
-	default:
-		return nil, fmt.Errorf("Footprint: link is expected but '%T' is found", obj)
-	}
-
-	dec, err := d.handler.Decode(obj)
-	if err != nil {
-		return nil, err
+// Prototype creates a protype Footprint
+func (d *Footprint) Prototype() data.Data {
+	return &Footprint{
+		Union: d.Union.Prototype().(*data.Union),
 	}
-
-	d.Base.MarkDefined()
-	return dec, nil
-}
-
-// ToJSON prepares the data for MarshalJSON
-func (d *Footprint) ToJSON() (interface{}, error) {
-	return d.handler.ToJSON()
-}
-
-// Resolve resolves the link
-func (d *Footprint) Resolve(path []string) (interface{}, []string, error) {
-	return d.handler.Resolve(path)
 }
@@ -12,13 +12,23 @@ const (
 	SchemaName = "stakeholder"
 )
 
-// Register registers the schema of stakeholder block
+// Register registers the schema of stakeholder block to the default registry
 func Register() {
-	block.RegisterIscnObjectFactory(
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers the schema of stakeholder block to the registry
+func RegisterTo(r *block.Registry) {
+	r.Register(
 		block.CodecStakeholder,
 		SchemaName,
 		newSchemaV1,
+		newSchemaV2,
+		newSchemaV3,
 	)
+
+	r.RegisterMigration(block.CodecStakeholder, 1, MigrateV1ToV2)
+	r.RegisterMigration(block.CodecStakeholder, 2, MigrateV2ToV3)
 }
 
 // ==================================================
@@ -94,15 +104,139 @@ func SchemaV1Prototype() data.Codec {
 
 // Validate the data
 func (o *schemaV1) Validate() error {
-	if o.typ.Get() == footprint {
-		if !o.footprint.IsDefined() {
+	return validateFootprint(o.typ, o.footprint)
+}
+
+// validateFootprint checks that only footprint stakeholders have a footprint
+func validateFootprint(typ *Type, fp *Footprint) error {
+	if typ.Get() == footprint {
+		if !fp.IsDefined() {
 			return fmt.Errorf("Footprint is missed")
 		}
 	} else {
-		if o.footprint.IsDefined() {
+		if fp.IsDefined() {
 			return fmt.Errorf("Footprint should not be set as this is not a footprint stakeholder")
 		}
 	}
 
 	return nil
 }
+
+// ==================================================
+// schemaV2
+// ==================================================
+
+// schemaV2 represents a stakeholder V2, the CIDs are encoded as tag 42 links
+type schemaV2 struct {
+	*base
+
+	typ       *Type
+	footprint *Footprint
+}
+
+var _ block.IscnObject = (*schemaV2)(nil)
+
+func newSchemaV2() (block.Codec, error) {
+	typ := NewType()
+	footprint := NewLinkFootprint()
+
+	schema := []data.Data{
+		typ,
+		data.NewLink("stakeholder", true, block.CodecEntity),
+		data.NewNumber("sharing", true, data.Uint32T),
+		footprint,
+	}
+
+	stakeholderBase, err := newBase(2, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV2{
+		base:      stakeholderBase,
+		typ:       typ,
+		footprint: footprint,
+	}
+	stakeholderBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// SchemaV2Prototype creates a prototype for schemaV2
+func SchemaV2Prototype() data.Codec {
+	res, _ := newSchemaV2()
+	return res
+}
+
+// Validate the data
+func (o *schemaV2) Validate() error {
+	return validateFootprint(o.typ, o.footprint)
+}
+
+// ==================================================
+// schemaV3
+// ==================================================
+
+// schemaV3 represents a stakeholder V3, the sharing is encoded as a native
+// CBOR integer
+type schemaV3 struct {
+	*base
+
+	typ       *Type
+	footprint *Footprint
+}
+
+var _ block.IscnObject = (*schemaV3)(nil)
+
+func newSchemaV3() (block.Codec, error) {
+	typ := NewType()
+	footprint := NewLinkFootprint()
+
+	schema := []data.Data{
+		typ,
+		data.NewLink("stakeholder", true, block.CodecEntity),
+		data.NewNativeNumber("sharing", true, data.Uint32T),
+		footprint,
+	}
+
+	stakeholderBase, err := newBase(3, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := schemaV3{
+		base:      stakeholderBase,
+		typ:       typ,
+		footprint: footprint,
+	}
+	stakeholderBase.SetValidator(obj.Validate)
+
+	return &obj, nil
+}
+
+// SchemaV3Prototype creates a prototype for schemaV3
+func SchemaV3Prototype() data.Codec {
+	res, _ := newSchemaV3()
+	return res
+}
+
+// Validate the data
+func (o *schemaV3) Validate() error {
+	return validateFootprint(o.typ, o.footprint)
+}
+
+// ==================================================
+// Migrations
+// ==================================================
+
+// MigrateV1ToV2 migrates a stakeholder V1 to V2, the CIDs are converted to
+// links
+func MigrateV1ToV2(m map[string]interface{}) ([]string, error) {
+	return nil, block.BytesToLinks(m, "stakeholder", "footprint")
+}
+
+// MigrateV2ToV3 migrates a stakeholder V2 to V3, the varint sharing is
+// decoded by the native number handler as is
+func MigrateV2ToV3(m map[string]interface{}) ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,61 @@
+package stakeholder
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// The CIDs of the same stakeholder in each version are pinned, as they change
+// when the encoding of the CIDs or the numbers changes: v1 encodes both as
+// byte strings, v2 encodes the CIDs as tag 42 links and v3 also encodes the
+// numbers as native CBOR integers
+func TestStakeholderCids(t *testing.T) {
+	r := block.NewRegistry()
+	RegisterTo(r)
+
+	h, err := mh.Sum([]byte("entity"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := map[string]interface{}{
+		"type":        "Creator",
+		"stakeholder": cid.NewCidV1(block.CodecEntity, h),
+		"sharing":     uint64(100),
+	}
+
+	cases := []struct {
+		version uint64
+		cid     string
+	}{
+		{1, "bahiqkeraim2pmmm62vropvsshzu3meekt2625nbz74fxut3sir2gjki55rua"},
+		{2, "bahiqkeraqrlain7oocsoom7mnm3uky2mrxjlogim6owf4lyq4vj4k7ktpfkq"},
+		{3, "bahiqkerakhm63rejvhofzhghj2eqwleaist37yt2pycfuhxcqlbz73yfz7sa"},
+	}
+
+	for _, c := range cases {
+		obj, err := r.Encode(block.CodecStakeholder, c.version, m)
+		if err != nil {
+			t.Errorf("v%d: %s", c.version, err)
+			continue
+		}
+
+		if obj.Cid().String() != c.cid {
+			t.Errorf("v%d: CID %s is expected but %s is found", c.version, c.cid, obj.Cid())
+		}
+
+		dec, err := r.Decode(obj.RawData(), obj.Cid())
+		if err != nil {
+			t.Errorf("v%d: %s", c.version, err)
+			continue
+		}
+
+		if !dec.Cid().Equals(obj.Cid()) {
+			t.Errorf("v%d: decoded CID %s is expected but %s is found", c.version, obj.Cid(), dec.Cid())
+		}
+	}
+}
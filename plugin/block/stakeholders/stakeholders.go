@@ -1,9 +1,14 @@
 package stakeholders
 
 import (
+	"fmt"
+
+	"github.com/ipfs/go-cid"
 	"github.com/likecoin/iscn-ipld/plugin/block"
 	"github.com/likecoin/iscn-ipld/plugin/block/data"
 	"github.com/likecoin/iscn-ipld/plugin/block/stakeholder"
+
+	node "github.com/ipfs/go-ipld-format"
 )
 
 const (
@@ -11,13 +16,23 @@ const (
 	SchemaName = "stakeholders"
 )
 
-// Register registers the schema of stakeholders block
+// Register registers the schema of stakeholders block to the default registry
 func Register() {
-	block.RegisterIscnObjectFactory(
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers the schema of stakeholders block to the registry
+func RegisterTo(r *block.Registry) {
+	r.Register(
 		block.CodecStakeholders,
 		SchemaName,
 		newSchemaV1,
+		newSchemaV2,
+		newSchemaV3,
 	)
+
+	r.RegisterMigration(block.CodecStakeholders, 1, migrateV1ToV2)
+	r.RegisterMigration(block.CodecStakeholders, 2, migrateV2ToV3)
 }
 
 // ==================================================
@@ -72,3 +87,140 @@ func newSchemaV1() (block.Codec, error) {
 		base: stakeholdersBase,
 	}, nil
 }
+
+// ==================================================
+// schemaV2
+// ==================================================
+
+// schemaV2 represents a stakeholders V2, the stakeholders are stakeholder V2
+type schemaV2 struct {
+	*base
+}
+
+var _ block.IscnObject = (*schemaV2)(nil)
+
+func newSchemaV2() (block.Codec, error) {
+	prototype := data.NewObject("_", true, stakeholder.SchemaV2Prototype)
+
+	schema := []data.Data{
+		data.NewDataArray("stakeholders", true, prototype),
+	}
+
+	stakeholdersBase, err := newBase(2, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemaV2{
+		base: stakeholdersBase,
+	}, nil
+}
+
+// ==================================================
+// schemaV3
+// ==================================================
+
+// schemaV3 represents a stakeholders V3, the stakeholders are stakeholder V3
+type schemaV3 struct {
+	*base
+}
+
+var _ block.IscnObject = (*schemaV3)(nil)
+
+func newSchemaV3() (block.Codec, error) {
+	prototype := data.NewObject("_", true, stakeholder.SchemaV3Prototype)
+
+	schema := []data.Data{
+		data.NewDataArray("stakeholders", true, prototype),
+	}
+
+	stakeholdersBase, err := newBase(3, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemaV3{
+		base: stakeholdersBase,
+	}, nil
+}
+
+// ==================================================
+// Entities
+// ==================================================
+
+// Entities returns the entities listed in the stakeholders block in order,
+// an entity listed more than once is returned once
+func Entities(obj block.IscnObject) ([]cid.Cid, error) {
+	if obj.Cid().Type() != block.CodecStakeholders {
+		return nil, fmt.Errorf("Stakeholders: %s is not a stakeholders block", obj)
+	}
+
+	value, _, err := obj.Resolve([]string{"stakeholders"})
+	if err != nil {
+		return nil, err
+	}
+
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Stakeholders: '[]interface{}' is expected but '%T' is found", value)
+	}
+
+	res := []cid.Cid{}
+	seen := map[cid.Cid]bool{}
+	for i, elem := range list {
+		r, ok := elem.(interface {
+			Resolve(path []string) (interface{}, []string, error)
+		})
+		if !ok {
+			return nil, fmt.Errorf("(Index %d) '%T' cannot be resolved", i, elem)
+		}
+
+		value, _, err := r.Resolve([]string{"stakeholder"})
+		if err != nil {
+			return nil, fmt.Errorf("(Index %d) %s", i, err)
+		}
+
+		link, ok := value.(*node.Link)
+		if !ok {
+			return nil, fmt.Errorf("(Index %d) a link is expected but '%T' is found", i, value)
+		}
+
+		if !seen[link.Cid] {
+			seen[link.Cid] = true
+			res = append(res, link.Cid)
+		}
+	}
+
+	return res, nil
+}
+
+// ==================================================
+// Migrations
+// ==================================================
+
+// migrateV1ToV2 migrates a stakeholders V1 to V2 by migrating each stakeholder
+func migrateV1ToV2(m map[string]interface{}) ([]string, error) {
+	stakeholders, ok := m["stakeholders"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Stakeholders: '[]interface{}' is expected but '%T' is found", m["stakeholders"])
+	}
+
+	for i, value := range stakeholders {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("(Index %d) 'map[string]interface{}' is expected but '%T' is found", i, value)
+		}
+
+		if _, err := stakeholder.MigrateV1ToV2(obj); err != nil {
+			return nil, fmt.Errorf("(Index %d) %s", i, err)
+		}
+	}
+
+	return nil, nil
+}
+
+// migrateV2ToV3 migrates a stakeholders V2 to V3, the varint sharing is
+// decoded by the native number handler as is
+func migrateV2ToV3(m map[string]interface{}) ([]string, error) {
+	return nil, nil
+}
@@ -0,0 +1,70 @@
+package stakeholders
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// The CIDs of the same stakeholders in each version are pinned, as they change
+// when the encoding of the CIDs or the numbers changes: v1 encodes both as
+// byte strings, v2 encodes the CIDs as tag 42 links and v3 also encodes the
+// numbers as native CBOR integers
+func TestStakeholdersCids(t *testing.T) {
+	r := block.NewRegistry()
+	RegisterTo(r)
+
+	h, err := mh.Sum([]byte("entity"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := map[string]interface{}{
+		"stakeholders": []interface{}{
+			map[string]interface{}{
+				"type":        "Creator",
+				"stakeholder": cid.NewCidV1(block.CodecEntity, h),
+				"sharing":     uint64(60),
+			},
+			map[string]interface{}{
+				"type":        "Contributor",
+				"stakeholder": cid.NewCidV1(block.CodecEntity, h),
+				"sharing":     uint64(40),
+			},
+		},
+	}
+
+	cases := []struct {
+		version uint64
+		cid     string
+	}{
+		{1, "bahtaierargchbzrbkjezcdymiux3nmqj4awphsfekgzvtwn4tjzdwlneuzfa"},
+		{2, "bahtaierakgik2hmjh67i3n7b3hj2flbyppbm2ewneudrp2ymshh6e76nmb4a"},
+		{3, "bahtaieraytn7wcwgwegte7p2cpcfnx5buae2ajmezlhf6biqakmiaocrrsra"},
+	}
+
+	for _, c := range cases {
+		obj, err := r.Encode(block.CodecStakeholders, c.version, m)
+		if err != nil {
+			t.Errorf("v%d: %s", c.version, err)
+			continue
+		}
+
+		if obj.Cid().String() != c.cid {
+			t.Errorf("v%d: CID %s is expected but %s is found", c.version, c.cid, obj.Cid())
+		}
+
+		dec, err := r.Decode(obj.RawData(), obj.Cid())
+		if err != nil {
+			t.Errorf("v%d: %s", c.version, err)
+			continue
+		}
+
+		if !dec.Cid().Equals(obj.Cid()) {
+			t.Errorf("v%d: decoded CID %s is expected but %s is found", c.version, obj.Cid(), dec.Cid())
+		}
+	}
+}
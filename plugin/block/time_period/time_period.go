@@ -12,9 +12,14 @@ const (
 	SchemaName = "timeperiod"
 )
 
-// Register registers the schema of time period block
+// Register registers the schema of time period block to the default registry
 func Register() {
-	block.RegisterIscnObjectFactory(
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers the schema of time period block to the registry
+func RegisterTo(r *block.Registry) {
+	r.Register(
 		block.CodecTimePeriod,
 		SchemaName,
 		newSchemaV1,
@@ -0,0 +1,350 @@
+package cosign
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/attestation"
+	"github.com/likecoin/iscn-ipld/plugin/block/stakeholders"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+)
+
+// ==================================================
+// Verifier
+// ==================================================
+
+// Verifier loads the stakeholder entities and resolves their DIDs to verify
+// the signatures of a draft
+type Verifier struct {
+	Loader   block.Loader
+	Resolver did.Resolver
+}
+
+// ==================================================
+// Draft
+// ==================================================
+
+// Draft is an ISCN record waiting for the consent of its stakeholders. The
+// stakeholders block is prepared first, each listed entity signs it with a
+// detached attestation and the kernel is only emitted when all of them are
+// verified
+type Draft struct {
+	stakeholders  block.IscnObject
+	kernel        map[string]interface{}
+	kernelVersion uint64
+	attestations  []block.IscnObject
+}
+
+// NewDraft prepares the stakeholders block of the latest version and the
+// kernel properties except "stakeholders", which is the link to the
+// stakeholders block. The kernel is checked by encoding it with the link
+func NewDraft(stakeholders map[string]interface{}, kernel map[string]interface{}) (*Draft, error) {
+	stakeholdersVersion, err := block.LatestVersion(block.CodecStakeholders)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := block.Encode(block.CodecStakeholders, stakeholdersVersion, stakeholders)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkEntities(obj); err != nil {
+		return nil, err
+	}
+
+	kernelVersion, err := block.LatestVersion(block.CodecISCN)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := kernel["stakeholders"]; ok {
+		return nil, fmt.Errorf("Draft: \"stakeholders\" of the kernel is set by the draft")
+	}
+
+	norm, err := block.NormalizeCustom(kernel)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Draft{
+		stakeholders:  obj,
+		kernel:        norm.(map[string]interface{}),
+		kernelVersion: kernelVersion,
+		attestations:  []block.IscnObject{},
+	}
+
+	if _, err := d.encodeKernel(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Stakeholders returns the draft stakeholders block to be signed
+func (d *Draft) Stakeholders() block.IscnObject {
+	return d.stakeholders
+}
+
+// Subject returns the CID of the draft stakeholders block to be signed
+func (d *Draft) Subject() cid.Cid {
+	return d.stakeholders.Cid()
+}
+
+// Payload returns the message to be signed by the key on behalf of the entity
+func (d *Draft) Payload(entity cid.Cid, key *did.PublicKey, timestamp string) ([]byte, error) {
+	return attestation.Payload(d.Subject(), entity, key, timestamp)
+}
+
+// Attestations returns the collected attestation blocks
+func (d *Draft) Attestations() []block.IscnObject {
+	return d.attestations
+}
+
+// AddAttestation verifies the attestation block and collects it, the
+// attestations which are not of the draft, not by a listed entity, fail the
+// verification or are by an entity which has signed are rejected
+func (d *Draft) AddAttestation(obj block.IscnObject, v *Verifier) error {
+	a, err := attestation.Parse(obj)
+	if err != nil {
+		return err
+	}
+
+	report, err := attestation.VerifyStakeholders(
+		d.stakeholders,
+		[]*attestation.Attestation{a},
+		v.Loader,
+		v.Resolver,
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Rejected) > 0 {
+		return report.Rejected[0].Err
+	}
+
+	for _, collected := range d.attestations {
+		prev, err := attestation.Parse(collected)
+		if err != nil {
+			return err
+		}
+
+		if prev.Signer.Equals(a.Signer) {
+			return fmt.Errorf("Draft: the entity %s has signed", a.Signer)
+		}
+	}
+
+	d.attestations = append(d.attestations, obj)
+	return nil
+}
+
+// AddSignature creates the attestation block of the detached signature and
+// collects it
+func (d *Draft) AddSignature(
+	entity cid.Cid,
+	key *did.PublicKey,
+	signature []byte,
+	timestamp string,
+	v *Verifier,
+) (block.IscnObject, error) {
+	m := map[string]interface{}{
+		"subject":   d.Subject(),
+		"signer":    entity,
+		"key":       key.Multibase(),
+		"signature": signature,
+	}
+
+	if timestamp != "" {
+		m["timestamp"] = timestamp
+	}
+
+	obj, err := block.Encode(block.CodecAttestation, 1, m)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := d.AddAttestation(obj, v); err != nil {
+		return nil, err
+	}
+
+	return obj, nil
+}
+
+// Status verifies the collected attestations again and reports the entities
+// which have signed and which are pending
+func (d *Draft) Status(v *Verifier) (*attestation.Report, error) {
+	attestations := []*attestation.Attestation{}
+	for _, obj := range d.attestations {
+		a, err := attestation.Parse(obj)
+		if err != nil {
+			return nil, err
+		}
+		attestations = append(attestations, a)
+	}
+
+	return attestation.VerifyStakeholders(d.stakeholders, attestations, v.Loader, v.Resolver)
+}
+
+// Pending returns the entities which have not signed
+func (d *Draft) Pending(v *Verifier) ([]cid.Cid, error) {
+	report, err := d.Status(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return report.Pending(), nil
+}
+
+// Finalize emits the kernel linking to the stakeholders block once all the
+// listed entities have signed and their attestations are verified
+func (d *Draft) Finalize(v *Verifier) (block.IscnObject, error) {
+	report, err := d.Status(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(report.Rejected) > 0 {
+		return nil, report.Rejected[0].Err
+	}
+
+	if pending := report.Pending(); len(pending) > 0 {
+		return nil, fmt.Errorf("Draft: %d of the stakeholders have not signed, e.g. %s", len(pending), pending[0])
+	}
+
+	return d.encodeKernel()
+}
+
+// Blocks returns the stakeholders block and the attestation blocks, which
+// should be stored with the kernel
+func (d *Draft) Blocks() []block.IscnObject {
+	return append([]block.IscnObject{d.stakeholders}, d.attestations...)
+}
+
+// checkEntities rejects the stakeholders block listing no entity, whose kernel
+// would be emitted without any signature
+func checkEntities(obj block.IscnObject) error {
+	entities, err := stakeholders.Entities(obj)
+	if err != nil {
+		return err
+	}
+
+	if len(entities) == 0 {
+		return fmt.Errorf("Draft: no entity is listed in the stakeholders")
+	}
+
+	return nil
+}
+
+func (d *Draft) encodeKernel() (block.IscnObject, error) {
+	m := map[string]interface{}{}
+	for key, value := range d.kernel {
+		m[key] = value
+	}
+	m["stakeholders"] = d.Subject()
+
+	return block.Encode(block.CodecISCN, d.kernelVersion, m)
+}
+
+// ==================================================
+// Serialization
+// ==================================================
+
+// rawBlock is a block in the serialized draft
+type rawBlock struct {
+	Cid  string `json:"cid"`
+	Data []byte `json:"data"`
+}
+
+// draftJSON is the serialized draft, the kernel properties are in the
+// canonical DAG-CBOR encoding
+type draftJSON struct {
+	Stakeholders  rawBlock   `json:"stakeholders"`
+	Kernel        []byte     `json:"kernel"`
+	KernelVersion uint64     `json:"kernelVersion"`
+	Attestations  []rawBlock `json:"attestations"`
+}
+
+// MarshalJSON serializes the draft so that it can be passed between services
+func (d *Draft) MarshalJSON() ([]byte, error) {
+	kernel, err := block.EncodeCanonical(d.kernel)
+	if err != nil {
+		return nil, err
+	}
+
+	res := draftJSON{
+		Stakeholders:  newRawBlock(d.stakeholders),
+		Kernel:        kernel,
+		KernelVersion: d.kernelVersion,
+		Attestations:  []rawBlock{},
+	}
+
+	for _, obj := range d.attestations {
+		res.Attestations = append(res.Attestations, newRawBlock(obj))
+	}
+
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON restores the serialized draft, the blocks are decoded and
+// checked against their CIDs, the attestations should be verified again with
+// Status before they are trusted
+func (d *Draft) UnmarshalJSON(b []byte) error {
+	var raw draftJSON
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	stakeholders, err := raw.Stakeholders.decode(block.CodecStakeholders)
+	if err != nil {
+		return err
+	}
+
+	if err := checkEntities(stakeholders); err != nil {
+		return err
+	}
+
+	kernel := map[string]interface{}{}
+	if err := cbor.DecodeInto(raw.Kernel, &kernel); err != nil {
+		return fmt.Errorf("Draft: invalid kernel (%s)", err)
+	}
+
+	attestations := []block.IscnObject{}
+	for i, r := range raw.Attestations {
+		obj, err := r.decode(block.CodecAttestation)
+		if err != nil {
+			return fmt.Errorf("(Index %d) %s", i, err)
+		}
+		attestations = append(attestations, obj)
+	}
+
+	d.stakeholders = stakeholders
+	d.kernel = kernel
+	d.kernelVersion = raw.KernelVersion
+	d.attestations = attestations
+	return nil
+}
+
+func newRawBlock(obj block.IscnObject) rawBlock {
+	return rawBlock{
+		Cid:  obj.Cid().String(),
+		Data: obj.RawData(),
+	}
+}
+
+func (r *rawBlock) decode(codec uint64) (block.IscnObject, error) {
+	c, err := cid.Decode(r.Cid)
+	if err != nil {
+		return nil, fmt.Errorf("Draft: invalid CID %q (%s)", r.Cid, err)
+	}
+
+	if c.Type() != codec {
+		return nil, fmt.Errorf("Draft: codec '0x%x' is expected but '0x%x' is found", codec, c.Type())
+	}
+
+	return block.Decode(r.Data, c)
+}
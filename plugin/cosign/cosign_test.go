@@ -0,0 +1,221 @@
+package cosign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/attestation"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+	"github.com/likecoin/iscn-ipld/plugin/iscn"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+func init() {
+	iscn.Register()
+}
+
+type loader map[cid.Cid]block.IscnObject
+
+func (l loader) Load(c cid.Cid) (block.IscnObject, error) {
+	obj, ok := l[c]
+	if !ok {
+		return nil, fmt.Errorf("%s is not found", c)
+	}
+	return obj, nil
+}
+
+func testCid(t *testing.T, codec uint64, s string) cid.Cid {
+	h, err := mh.Sum([]byte(s), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cid.NewCidV1(codec, h)
+}
+
+func newKernel(t *testing.T) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        make([]byte, 32),
+		"timestamp": "2020-01-01T12:34:56Z",
+		"version":   uint64(1),
+		"rights":    testCid(t, block.CodecRights, "rights"),
+		"content":   testCid(t, block.CodecContent, "content"),
+	}
+}
+
+// party is an entity with its signing key
+type party struct {
+	id     cid.Cid
+	signer did.Signer
+}
+
+func newParty(t *testing.T, l loader, b byte, isEd25519 bool) *party {
+	var s did.Signer
+	var err error
+	m := map[string]interface{}{}
+	if isEd25519 {
+		s, err = did.NewEd25519Signer(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{b}, 32)))
+		if err == nil {
+			m["did"] = s.PublicKey().DIDKey()
+		}
+	} else {
+		s, err = did.NewSecp256k1Signer(bytes.Repeat([]byte{b}, 32))
+		if err == nil {
+			var address string
+			address, err = s.PublicKey().Bech32Address("cosmos")
+			m["id"] = "lcc://id/" + address
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	obj, err := block.Encode(block.CodecEntity, 4, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l[obj.Cid()] = obj
+
+	return &party{id: obj.Cid(), signer: s}
+}
+
+func (p *party) sign(t *testing.T, d *Draft, v *Verifier) error {
+	timestamp := "2020-01-01T00:00:00Z"
+	payload, err := d.Payload(p.id, p.signer.PublicKey(), timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signature, err := p.signer.Sign(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = d.AddSignature(p.id, p.signer.PublicKey(), signature, timestamp, v)
+	return err
+}
+
+func stakeholdersOf(parties ...*party) map[string]interface{} {
+	list := []interface{}{}
+	for _, p := range parties {
+		list = append(list, map[string]interface{}{
+			"type":        "Creator",
+			"stakeholder": p.id,
+			"sharing":     uint64(1),
+		})
+	}
+	return map[string]interface{}{"stakeholders": list}
+}
+
+func checkPending(t *testing.T, d *Draft, v *Verifier, expected ...*party) {
+	t.Helper()
+
+	pending, err := d.Pending(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pending) != len(expected) {
+		t.Fatalf("Pending: %d entities are expected but %v is found", len(expected), pending)
+	}
+
+	for i, p := range expected {
+		if !pending[i].Equals(p.id) {
+			t.Errorf("Pending: %s is expected but %s is found", p.id, pending[i])
+		}
+	}
+}
+
+func TestDraft(t *testing.T) {
+	l := loader{}
+	v := &Verifier{Loader: l, Resolver: did.DefaultResolver}
+	creator := newParty(t, l, 1, false)
+	editor := newParty(t, l, 2, true)
+	outsider := newParty(t, l, 3, false)
+
+	d, err := NewDraft(stakeholdersOf(creator, editor), newKernel(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPending(t, d, v, creator, editor)
+
+	if _, err := d.Finalize(v); err == nil {
+		t.Errorf("Finalize: the kernel is emitted without any signature")
+	}
+
+	if err := creator.sign(t, d, v); err != nil {
+		t.Fatal(err)
+	}
+	checkPending(t, d, v, editor)
+
+	if err := creator.sign(t, d, v); err == nil {
+		t.Errorf("AddSignature: the entity has signed twice")
+	}
+
+	if err := outsider.sign(t, d, v); err == nil {
+		t.Errorf("AddSignature: the unlisted entity is accepted")
+	}
+
+	other, err := attestation.Sign(testCid(t, block.CodecStakeholders, "other"), editor.id, editor.signer, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.AddAttestation(other, v); err == nil {
+		t.Errorf("AddAttestation: the attestation of another subject is accepted")
+	}
+
+	if _, err := d.Finalize(v); err == nil {
+		t.Errorf("Finalize: the kernel is emitted while the editor is pending")
+	}
+
+	// The draft is passed to another service
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := &Draft{}
+	if err := json.Unmarshal(b, restored); err != nil {
+		t.Fatal(err)
+	}
+
+	if !restored.Subject().Equals(d.Subject()) || len(restored.Attestations()) != 1 {
+		t.Fatalf("UnmarshalJSON: the draft of %s is expected but %s is found", d.Subject(), restored.Subject())
+	}
+	checkPending(t, restored, v, editor)
+
+	if err := editor.sign(t, restored, v); err != nil {
+		t.Fatal(err)
+	}
+	checkPending(t, restored, v)
+
+	kernel, err := restored.Finalize(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	link, err := kernel.GetCid("stakeholders")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !link.Equals(d.Subject()) {
+		t.Errorf("Finalize: the stakeholders %s is expected but %s is found", d.Subject(), link)
+	}
+
+	if blocks := restored.Blocks(); len(blocks) != 3 {
+		t.Errorf("Blocks: 3 blocks are expected but %d is found", len(blocks))
+	}
+}
+
+func TestDraftWithoutEntities(t *testing.T) {
+	if _, err := NewDraft(stakeholdersOf(), newKernel(t)); err == nil {
+		t.Errorf("NewDraft: the stakeholders without any entity is accepted")
+	}
+}
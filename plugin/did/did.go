@@ -0,0 +1,185 @@
+package did
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/likecoin/iscn-ipld/plugin/identifier"
+)
+
+// ==================================================
+// DID
+// ==================================================
+
+// DID is a W3C decentralized identifier, e.g. "did:key:z6Mk..."
+type DID struct {
+	Method string
+	ID     string
+}
+
+// Parse parses the DID, the DID URL parts, e.g. the fragment, are not
+// accepted
+func Parse(s string) (*DID, error) {
+	if _, err := identifier.Normalize(identifier.SchemeDID, s); err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(s, ":", 3)
+	return &DID{
+		Method: parts[1],
+		ID:     parts[2],
+	}, nil
+}
+
+// String returns the DID
+func (d *DID) String() string {
+	return fmt.Sprintf("did:%s:%s", d.Method, d.ID)
+}
+
+// ==================================================
+// Document
+// ==================================================
+
+// DocumentContext is the JSON-LD context of the DID documents
+const DocumentContext = "https://www.w3.org/ns/did/v1"
+
+// VerificationMethod is a verification method of a DID document, only the
+// public keys in the multibase form are supported
+type VerificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase,omitempty"`
+}
+
+// PublicKey returns the public key of the verification method
+func (m *VerificationMethod) PublicKey() (*PublicKey, error) {
+	if m.PublicKeyMultibase == "" {
+		return nil, fmt.Errorf("DID: verification method %q has no multibase public key", m.ID)
+	}
+
+	return ParseMultibaseKey(m.PublicKeyMultibase)
+}
+
+// Document is a DID document
+type Document struct {
+	Context            []string             `json:"@context"`
+	ID                 string               `json:"id"`
+	Controller         []string             `json:"controller,omitempty"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod,omitempty"`
+	Authentication     []string             `json:"authentication,omitempty"`
+	AssertionMethod    []string             `json:"assertionMethod,omitempty"`
+}
+
+// AssertionKeys returns the public keys of the assertion methods, which are
+// used for signing ISCN records
+func (d *Document) AssertionKeys() ([]*PublicKey, error) {
+	res := []*PublicKey{}
+	for _, ref := range d.AssertionMethod {
+		method, err := d.verificationMethod(ref)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := method.PublicKey()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, key)
+	}
+
+	return res, nil
+}
+
+// verificationMethod returns the verification method of the reference, which
+// is the DID URL of the method or its fragment
+func (d *Document) verificationMethod(ref string) (*VerificationMethod, error) {
+	if strings.HasPrefix(ref, "#") {
+		ref = d.ID + ref
+	}
+
+	for i, method := range d.VerificationMethod {
+		id := method.ID
+		if strings.HasPrefix(id, "#") {
+			id = d.ID + id
+		}
+
+		if id == ref {
+			return &d.VerificationMethod[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("DID: verification method %q is not found in %q", ref, d.ID)
+}
+
+// ==================================================
+// Resolver
+// ==================================================
+
+// ErrNotFound is returned when the DID cannot be resolved
+var ErrNotFound = errors.New("DID: not found")
+
+// Resolver resolves the DIDs to their DID documents
+type Resolver interface {
+	Resolve(did string) (*Document, error)
+}
+
+var resolversLock sync.RWMutex
+var resolvers = map[string]Resolver{}
+
+// RegisterResolver registers the resolver of the DID method, the registered
+// resolver is replaced
+func RegisterResolver(method string, resolver Resolver) {
+	resolversLock.Lock()
+	defer resolversLock.Unlock()
+
+	resolvers[method] = resolver
+}
+
+// Methods returns the DID methods with a registered resolver
+func Methods() []string {
+	resolversLock.RLock()
+	defer resolversLock.RUnlock()
+
+	methods := make([]string, 0, len(resolvers))
+	for method := range resolvers {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	return methods
+}
+
+// Resolve resolves the DID with the resolver registered for its method
+func Resolve(s string) (*Document, error) {
+	d, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	resolversLock.RLock()
+	resolver, ok := resolvers[d.Method]
+	resolversLock.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("DID: no resolver for method %q", d.Method)
+	}
+
+	return resolver.Resolve(s)
+}
+
+// DefaultResolver resolves the DIDs with the registered resolvers
+var DefaultResolver Resolver = resolverFunc(Resolve)
+
+type resolverFunc func(did string) (*Document, error)
+
+func (f resolverFunc) Resolve(did string) (*Document, error) {
+	return f(did)
+}
+
+func init() {
+	RegisterResolver(MethodKey, KeyResolver{})
+}
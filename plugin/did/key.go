@@ -0,0 +1,179 @@
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/likecoin/iscn-ipld/plugin/identifier"
+	"golang.org/x/crypto/ripemd160"
+)
+
+// ==================================================
+// PublicKey
+// ==================================================
+
+// KeyType is the type of a public key
+type KeyType string
+
+// Types of the supported public keys
+const (
+	Ed25519   KeyType = "ed25519"
+	Secp256k1 KeyType = "secp256k1"
+)
+
+// Multicodec codes of the public keys, as varints
+var (
+	ed25519Multicodec   = []byte{0xed, 0x01}
+	secp256k1Multicodec = []byte{0xe7, 0x01}
+)
+
+// Verification method types of the public keys
+const (
+	Ed25519VerificationKey2020        = "Ed25519VerificationKey2020"
+	EcdsaSecp256k1VerificationKey2019 = "EcdsaSecp256k1VerificationKey2019"
+)
+
+// SignatureSize is the length of a signature in bytes, the secp256k1
+// signature is the 32-byte R and S in the Cosmos SDK form
+const SignatureSize = 64
+
+// PublicKey is a public key which can verify signatures, the secp256k1 key
+// is in the 33-byte compressed form
+type PublicKey struct {
+	Type  KeyType
+	Bytes []byte
+}
+
+// NewPublicKey checks the public key of the type
+func NewPublicKey(typ KeyType, key []byte) (*PublicKey, error) {
+	switch typ {
+	case Ed25519:
+		if len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf(
+				"DID: ed25519 public key should be %d bytes but %d is found",
+				ed25519.PublicKeySize,
+				len(key),
+			)
+		}
+	case Secp256k1:
+		pub, err := btcec.ParsePubKey(key, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("DID: invalid secp256k1 public key (%s)", err)
+		}
+		key = pub.SerializeCompressed()
+	default:
+		return nil, fmt.Errorf("DID: unknown key type %q", typ)
+	}
+
+	return &PublicKey{
+		Type:  typ,
+		Bytes: key,
+	}, nil
+}
+
+// Equal checks whether the keys are the same
+func (k *PublicKey) Equal(other *PublicKey) bool {
+	return k.Type == other.Type && string(k.Bytes) == string(other.Bytes)
+}
+
+// Verify verifies the signature of the message, the secp256k1 signature is
+// over the SHA-256 digest of the message and should be in the low-S form
+func (k *PublicKey) Verify(msg []byte, sig []byte) bool {
+	if len(sig) != SignatureSize {
+		return false
+	}
+
+	switch k.Type {
+	case Ed25519:
+		return ed25519.Verify(ed25519.PublicKey(k.Bytes), msg, sig)
+	case Secp256k1:
+		pub, err := btcec.ParsePubKey(k.Bytes, btcec.S256())
+		if err != nil {
+			return false
+		}
+
+		signature := &btcec.Signature{
+			R: new(big.Int).SetBytes(sig[:32]),
+			S: new(big.Int).SetBytes(sig[32:]),
+		}
+
+		// Reject the malleable high-S form
+		if signature.S.Cmp(halfOrder) > 0 {
+			return false
+		}
+
+		digest := sha256.Sum256(msg)
+		return signature.Verify(digest[:], pub)
+	}
+
+	return false
+}
+
+var halfOrder = new(big.Int).Rsh(btcec.S256().N, 1)
+
+// Address returns the raw Cosmos SDK address of the key, which is
+// RIPEMD-160(SHA-256(key)) for secp256k1 and the first 20 bytes of
+// SHA-256(key) for ed25519
+func (k *PublicKey) Address() []byte {
+	digest := sha256.Sum256(k.Bytes)
+	if k.Type == Ed25519 {
+		return digest[:identifier.AddressLength]
+	}
+
+	h := ripemd160.New()
+	h.Write(digest[:])
+	return h.Sum(nil)
+}
+
+// Bech32Address returns the bech32 address of the key with the human
+// readable part
+func (k *PublicKey) Bech32Address(hrp string) (string, error) {
+	return identifier.EncodeAddress(hrp, k.Address())
+}
+
+// Multibase returns the public key in the multibase base58btc form of
+// did:key, e.g. "z6Mk..."
+func (k *PublicKey) Multibase() string {
+	prefix := ed25519Multicodec
+	if k.Type == Secp256k1 {
+		prefix = secp256k1Multicodec
+	}
+
+	return "z" + base58.Encode(append(append([]byte{}, prefix...), k.Bytes...))
+}
+
+// DIDKey returns the did:key of the public key
+func (k *PublicKey) DIDKey() string {
+	return "did:key:" + k.Multibase()
+}
+
+// VerificationMethodType returns the verification method type of the key
+func (k *PublicKey) VerificationMethodType() string {
+	if k.Type == Secp256k1 {
+		return EcdsaSecp256k1VerificationKey2019
+	}
+	return Ed25519VerificationKey2020
+}
+
+// ParseMultibaseKey parses the public key in the multibase base58btc form
+// with the multicodec prefix
+func ParseMultibaseKey(s string) (*PublicKey, error) {
+	if !strings.HasPrefix(s, "z") {
+		return nil, fmt.Errorf("DID: %q is not in the base58btc multibase form", s)
+	}
+
+	b := base58.Decode(s[1:])
+	switch {
+	case len(b) > 2 && string(b[:2]) == string(ed25519Multicodec):
+		return NewPublicKey(Ed25519, b[2:])
+	case len(b) > 2 && string(b[:2]) == string(secp256k1Multicodec):
+		return NewPublicKey(Secp256k1, b[2:])
+	}
+
+	return nil, fmt.Errorf("DID: %q is not an ed25519 or secp256k1 public key", s)
+}
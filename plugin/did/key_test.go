@@ -0,0 +1,203 @@
+package did
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func mustHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// The public keys of the examples of the did:key specification
+func TestParseMultibaseKey(t *testing.T) {
+	cases := []struct {
+		multibase string
+		typ       KeyType
+		key       string
+	}{
+		{
+			"z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp",
+			Ed25519,
+			"3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29",
+		},
+		{
+			"z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+			Ed25519,
+			"2e6fcce36701dc791488e0d0b1745cc1e33a4c1c9fcc41c63bd343dbbe0970e6",
+		},
+		{
+			"zQ3shokFTS3brHcDQrn82RUDfCZESWL1ZdCEJwekUDPQiYBme",
+			Secp256k1,
+			"03874c15c7fda20e539c6e5ba573c139884c351188799f5458b4b41f7924f235cd",
+		},
+	}
+
+	for _, c := range cases {
+		key, err := ParseMultibaseKey(c.multibase)
+		if err != nil {
+			t.Errorf("ParseMultibaseKey(%q): %s", c.multibase, err)
+			continue
+		}
+
+		if key.Type != c.typ || !bytes.Equal(key.Bytes, mustHex(c.key)) {
+			t.Errorf("ParseMultibaseKey(%q): %s key %s is expected but %s key %x is found",
+				c.multibase, c.typ, c.key, key.Type, key.Bytes)
+		}
+
+		if key.Multibase() != c.multibase {
+			t.Errorf("Multibase: %q is expected but %q is found", c.multibase, key.Multibase())
+		}
+
+		doc, err := Resolve("did:key:" + c.multibase)
+		if err != nil {
+			t.Errorf("Resolve(did:key:%s): %s", c.multibase, err)
+			continue
+		}
+
+		keys, err := doc.AssertionKeys()
+		if err != nil {
+			t.Errorf("AssertionKeys: %s", err)
+			continue
+		}
+
+		if len(keys) != 1 || !keys[0].Equal(key) {
+			t.Errorf("Resolve(did:key:%s): the assertion key is not the key", c.multibase)
+		}
+	}
+
+	for _, s := range []string{
+		"6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDooWp",
+		"z6MkiTBz1ymuepAQ4HEHYSF1H8quG5GLVVQR3djdX3mDoo",
+		"z2J9gaYxrKVpdoG9A4gRnmpnRCcxU6agDtFVVBVdn1JedouoZN7SzcyREXXzWgt3gGiwpoHq7K68X4m32D8HgzG8wv3sY5j7",
+	} {
+		if key, err := ParseMultibaseKey(s); err == nil {
+			t.Errorf("ParseMultibaseKey(%q): error is expected but %s is found", s, key.Multibase())
+		}
+	}
+}
+
+// The Cosmos SDK addresses are RIPEMD-160(SHA-256(key)) for secp256k1, e.g.
+// of the private key 1 whose hash is the BIP 173 example, and the first 20
+// bytes of SHA-256(key) for ed25519, e.g. of the RFC 8032 test vector 1
+func TestAddress(t *testing.T) {
+	cases := []struct {
+		typ     KeyType
+		key     string
+		address string
+	}{
+		{
+			Secp256k1,
+			"0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			"cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c",
+		},
+		{
+			Ed25519,
+			"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+			"cosmos1y8lrrhap2j3xzcntlp2qgm7jyudhhm2tc7hkue",
+		},
+	}
+
+	for _, c := range cases {
+		key, err := NewPublicKey(c.typ, mustHex(c.key))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		address, err := key.Bech32Address("cosmos")
+		if err != nil {
+			t.Errorf("Bech32Address(%s): %s", c.key, err)
+			continue
+		}
+
+		if address != c.address {
+			t.Errorf("Bech32Address(%s): %s is expected but %s is found", c.key, c.address, address)
+		}
+	}
+
+	// The signer of the private key 1
+	one := make([]byte, 32)
+	one[31] = 1
+	signer, err := NewSecp256k1Signer(one)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(signer.PublicKey().Bytes) != cases[0].key {
+		t.Errorf("NewSecp256k1Signer: public key %s is expected but %x is found",
+			cases[0].key, signer.PublicKey().Bytes)
+	}
+}
+
+func TestVerifySecp256k1(t *testing.T) {
+	signer, err := NewSecp256k1Signer(bytes.Repeat([]byte{7}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("message")
+	sig, err := signer.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := signer.PublicKey()
+	if !key.Verify(msg, sig) {
+		t.Fatalf("Verify: the signature should be valid")
+	}
+
+	if key.Verify([]byte("other message"), sig) {
+		t.Errorf("Verify: the signature of another message should be invalid")
+	}
+
+	// The same signature in the malleable high-S form
+	s := new(big.Int).SetBytes(sig[32:])
+	highS := new(big.Int).Sub(btcec.S256().N, s)
+	malleable := make([]byte, SignatureSize)
+	copy(malleable, sig[:32])
+	b := highS.Bytes()
+	copy(malleable[SignatureSize-len(b):], b)
+
+	if key.Verify(msg, malleable) {
+		t.Errorf("Verify: the high-S signature should be rejected")
+	}
+
+	if key.Verify(msg, sig[:SignatureSize-1]) {
+		t.Errorf("Verify: the truncated signature should be rejected")
+	}
+}
+
+func TestVerifyEd25519(t *testing.T) {
+	signer, err := NewEd25519Signer(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("message")
+	sig, err := signer.Sign(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !signer.PublicKey().Verify(msg, sig) {
+		t.Fatalf("Verify: the signature should be valid")
+	}
+
+	other, err := NewEd25519Signer(ed25519.NewKeyFromSeed(bytes.Repeat([]byte{8}, 32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if other.PublicKey().Verify(msg, sig) {
+		t.Errorf("Verify: the signature should be rejected by another key")
+	}
+}
@@ -0,0 +1,115 @@
+package did
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// ==================================================
+// KeyResolver
+// ==================================================
+
+// MethodKey is the did:key method
+const MethodKey = "key"
+
+// KeyResolver resolves did:key offline, the DID document is derived from the
+// public key in the DID
+type KeyResolver struct{}
+
+var _ Resolver = KeyResolver{}
+
+// Resolve resolves the did:key
+func (KeyResolver) Resolve(s string) (*Document, error) {
+	d, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Method != MethodKey {
+		return nil, fmt.Errorf("DID: %q is not a did:key", s)
+	}
+
+	key, err := ParseMultibaseKey(d.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyDocument(key), nil
+}
+
+// NewKeyDocument creates the DID document of the did:key of the public key
+func NewKeyDocument(key *PublicKey) *Document {
+	id := key.DIDKey()
+	method := id + "#" + key.Multibase()
+
+	return &Document{
+		Context: []string{DocumentContext},
+		ID:      id,
+		VerificationMethod: []VerificationMethod{
+			{
+				ID:                 method,
+				Type:               key.VerificationMethodType(),
+				Controller:         id,
+				PublicKeyMultibase: key.Multibase(),
+			},
+		},
+		Authentication:  []string{method},
+		AssertionMethod: []string{method},
+	}
+}
+
+// ==================================================
+// FileResolver
+// ==================================================
+
+// FileResolver resolves the DIDs with the DID documents in a directory, the
+// document of a DID is in the file named by the path-escaped DID with
+// ".json", e.g. "did:web:example.com.json". It is a stand-in for the
+// resolvers of the methods requiring network access, e.g. did:web
+type FileResolver struct {
+	Dir string
+}
+
+var _ Resolver = (*FileResolver)(nil)
+
+// NewFileResolver creates a resolver of the DID documents in the directory
+func NewFileResolver(dir string) *FileResolver {
+	return &FileResolver{
+		Dir: dir,
+	}
+}
+
+// Path returns the path of the DID document of the DID
+func (r *FileResolver) Path(s string) string {
+	return filepath.Join(r.Dir, url.PathEscape(s)+".json")
+}
+
+// Resolve resolves the DID
+func (r *FileResolver) Resolve(s string) (*Document, error) {
+	if _, err := Parse(s); err != nil {
+		return nil, err
+	}
+
+	b, err := ioutil.ReadFile(r.Path(s))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Document{}
+	if err := json.Unmarshal(b, doc); err != nil {
+		return nil, fmt.Errorf("DID: invalid DID document of %q (%s)", s, err)
+	}
+
+	if doc.ID != s {
+		return nil, fmt.Errorf("DID: the DID document of %q is of %q", s, doc.ID)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,111 @@
+package did
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// ==================================================
+// Signer
+// ==================================================
+
+// Signer signs messages with a private key, the signatures can be verified by
+// its public key
+type Signer interface {
+	PublicKey() *PublicKey
+	Sign(msg []byte) ([]byte, error)
+}
+
+// Ed25519Signer is a signer of an ed25519 private key
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+	pub *PublicKey
+}
+
+var _ Signer = (*Ed25519Signer)(nil)
+
+// NewEd25519Signer creates a signer of the ed25519 private key
+func NewEd25519Signer(key ed25519.PrivateKey) (*Ed25519Signer, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf(
+			"DID: ed25519 private key should be %d bytes but %d is found",
+			ed25519.PrivateKeySize,
+			len(key),
+		)
+	}
+
+	pub, err := NewPublicKey(Ed25519, key.Public().(ed25519.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ed25519Signer{
+		key: key,
+		pub: pub,
+	}, nil
+}
+
+// PublicKey returns the public key
+func (s *Ed25519Signer) PublicKey() *PublicKey {
+	return s.pub
+}
+
+// Sign signs the message
+func (s *Ed25519Signer) Sign(msg []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, msg), nil
+}
+
+// Secp256k1Signer is a signer of a secp256k1 private key, the signatures are
+// over the SHA-256 digest of the messages in the Cosmos SDK form
+type Secp256k1Signer struct {
+	key *btcec.PrivateKey
+	pub *PublicKey
+}
+
+var _ Signer = (*Secp256k1Signer)(nil)
+
+// NewSecp256k1Signer creates a signer of the 32-byte secp256k1 private key
+func NewSecp256k1Signer(key []byte) (*Secp256k1Signer, error) {
+	if len(key) != btcec.PrivKeyBytesLen {
+		return nil, fmt.Errorf(
+			"DID: secp256k1 private key should be %d bytes but %d is found",
+			btcec.PrivKeyBytesLen,
+			len(key),
+		)
+	}
+
+	priv, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), key)
+	pub, err := NewPublicKey(Secp256k1, pubKey.SerializeCompressed())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Secp256k1Signer{
+		key: priv,
+		pub: pub,
+	}, nil
+}
+
+// PublicKey returns the public key
+func (s *Secp256k1Signer) PublicKey() *PublicKey {
+	return s.pub
+}
+
+// Sign signs the message, the signature is in the low-S form
+func (s *Secp256k1Signer) Sign(msg []byte) ([]byte, error) {
+	digest := sha256.Sum256(msg)
+	sig, err := s.key.Sign(digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, SignatureSize)
+	r := sig.R.Bytes()
+	ss := sig.S.Bytes()
+	copy(res[32-len(r):32], r)
+	copy(res[64-len(ss):], ss)
+	return res, nil
+}
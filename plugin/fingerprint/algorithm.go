@@ -0,0 +1,191 @@
+package fingerprint
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+	"sync"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+	"lukechampine.com/blake3"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// ==================================================
+// Algorithm
+// ==================================================
+
+// ErrUnknownAlgorithm is the error of an algorithm which is not registered
+var ErrUnknownAlgorithm = errors.New("Fingerprint: unknown algorithm")
+
+// HashFunc returns a new hash.Hash for computing a digest
+type HashFunc func() hash.Hash
+
+// Algorithm is a hash algorithm which can be used in a fingerprint
+type Algorithm struct {
+	name    string
+	size    int
+	code    uint64
+	newHash HashFunc
+}
+
+// Name returns the name of the algorithm used in the fingerprint URI
+func (a *Algorithm) Name() string {
+	return a.name
+}
+
+// Size returns the length of the digest in bytes
+func (a *Algorithm) Size() int {
+	return a.size
+}
+
+// MultihashCode returns the multihash code of the algorithm if there is one
+func (a *Algorithm) MultihashCode() (uint64, bool) {
+	return a.code, a.code != 0
+}
+
+// New returns a new hash.Hash of the algorithm
+func (a *Algorithm) New() hash.Hash {
+	return a.newHash()
+}
+
+var algorithmsLock sync.RWMutex
+var algorithms = map[string]*Algorithm{}
+
+// RegisterAlgorithm registers a hash algorithm for fingerprint
+func RegisterAlgorithm(name string, size int, newHash HashFunc) error {
+	if !algorithmPattern.MatchString(name) {
+		return fmt.Errorf("Fingerprint: invalid algorithm name %q", name)
+	}
+
+	if size <= 0 {
+		return fmt.Errorf("Fingerprint: invalid digest size %d of %q", size, name)
+	}
+
+	algorithmsLock.Lock()
+	defer algorithmsLock.Unlock()
+
+	if _, exist := algorithms[name]; exist {
+		return fmt.Errorf("Fingerprint: algorithm %q is already registered", name)
+	}
+
+	algorithms[name] = &Algorithm{
+		name:    name,
+		size:    size,
+		newHash: newHash,
+	}
+
+	return nil
+}
+
+// GetAlgorithm returns the registered algorithm of the name
+func GetAlgorithm(name string) (*Algorithm, error) {
+	algorithmsLock.RLock()
+	defer algorithmsLock.RUnlock()
+
+	algorithm, ok := algorithms[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownAlgorithm, name)
+	}
+
+	return algorithm, nil
+}
+
+// Algorithms returns the names of all registered algorithms
+func Algorithms() []string {
+	algorithmsLock.RLock()
+	defer algorithmsLock.RUnlock()
+
+	names := make([]string, 0, len(algorithms))
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// RegisterMultihashCode associates a registered algorithm with a multihash
+// code, which enables the conversion between different forms of fingerprint
+func RegisterMultihashCode(name string, code uint64) error {
+	if !mh.ValidCode(code) || code == mh.IDENTITY {
+		return fmt.Errorf("Fingerprint: invalid multihash code 0x%x", code)
+	}
+
+	algorithmsLock.Lock()
+	defer algorithmsLock.Unlock()
+
+	algorithm, ok := algorithms[name]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownAlgorithm, name)
+	}
+
+	for _, a := range algorithms {
+		if a.code == code {
+			return fmt.Errorf("Fingerprint: multihash code 0x%x is already used by %q", code, a.name)
+		}
+	}
+
+	algorithm.code = code
+	return nil
+}
+
+func algorithmByCode(code uint64) (*Algorithm, bool) {
+	algorithmsLock.RLock()
+	defer algorithmsLock.RUnlock()
+
+	for _, a := range algorithms {
+		if a.code != 0 && a.code == code {
+			return a, true
+		}
+	}
+
+	return nil, false
+}
+
+func mustRegisterAlgorithm(name string, size int, newHash HashFunc) {
+	if err := RegisterAlgorithm(name, size, newHash); err != nil {
+		panic(err)
+	}
+}
+
+func mustRegisterMultihashCode(name string, code uint64) {
+	if err := RegisterMultihashCode(name, code); err != nil {
+		panic(err)
+	}
+}
+
+func newBlake2b(size int) HashFunc {
+	return func() hash.Hash {
+		// New only fails with an invalid size or key
+		h, err := blake2b.New(size, nil)
+		if err != nil {
+			panic(err)
+		}
+		return h
+	}
+}
+
+func init() {
+	mustRegisterAlgorithm("sha256", sha256.Size, sha256.New)
+	mustRegisterAlgorithm("sha512", sha512.Size, sha512.New)
+	mustRegisterAlgorithm("sha3-256", 32, sha3.New256)
+	mustRegisterAlgorithm("sha3-512", 64, sha3.New512)
+	mustRegisterAlgorithm("blake2b-256", blake2b.Size256, newBlake2b(blake2b.Size256))
+	mustRegisterAlgorithm("blake2b-512", blake2b.Size, newBlake2b(blake2b.Size))
+	mustRegisterAlgorithm("blake3", 32, func() hash.Hash {
+		return blake3.New(32, nil)
+	})
+
+	mustRegisterMultihashCode("sha256", mh.SHA2_256)
+	mustRegisterMultihashCode("sha512", mh.SHA2_512)
+	mustRegisterMultihashCode("sha3-256", mh.SHA3_256)
+	mustRegisterMultihashCode("sha3-512", mh.SHA3_512)
+	mustRegisterMultihashCode("blake2b-256", mh.BLAKE2B_MIN+blake2b.Size256-1)
+	mustRegisterMultihashCode("blake2b-512", mh.BLAKE2B_MAX)
+}
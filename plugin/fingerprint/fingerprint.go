@@ -0,0 +1,392 @@
+package fingerprint
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	mbase "github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+)
+
+const (
+	// Scheme of the fingerprint URI
+	Scheme = "hash"
+
+	// ContentKey is the key of fingerprint in content block
+	ContentKey = "fingerprint"
+)
+
+var algorithmPattern = regexp.MustCompile(`^[0-9a-z][0-9a-z-]*$`)
+var digestPattern = regexp.MustCompile(`^[0-9a-f]+$`)
+
+// ==================================================
+// Kind
+// ==================================================
+
+// Kind is a enum type for the form of a fingerprint
+type Kind int
+
+const (
+	// HashURI represents "hash://<algorithm>/<hex digest>"
+	HashURI Kind = iota
+
+	// CID represents an IPFS CID of the content
+	CID
+
+	// Multihash represents a multibase encoded multihash of the content
+	Multihash
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case HashURI:
+		return "hash URI"
+	case CID:
+		return "CID"
+	case Multihash:
+		return "multihash"
+	}
+
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// ==================================================
+// Fingerprint
+// ==================================================
+
+// Fingerprint is the digest of the content computed by a hash algorithm
+type Fingerprint struct {
+	kind      Kind
+	algorithm *Algorithm
+	code      uint64
+	digest    []byte
+	c         cid.Cid
+}
+
+// New creates a fingerprint from the algorithm name and the digest
+func New(algorithm string, digest []byte) (*Fingerprint, error) {
+	a, err := GetAlgorithm(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(digest) != a.Size() {
+		return nil, fmt.Errorf(
+			"Fingerprint: digest of %q should length %d but %d is found",
+			a.Name(),
+			a.Size(),
+			len(digest),
+		)
+	}
+
+	code, _ := a.MultihashCode()
+	return &Fingerprint{
+		kind:      HashURI,
+		algorithm: a,
+		code:      code,
+		digest:    append([]byte{}, digest...),
+	}, nil
+}
+
+// Parse parses the fingerprint URI "hash://<algorithm>/<hex digest>"
+func Parse(s string) (*Fingerprint, error) {
+	prefix := Scheme + "://"
+	if !strings.HasPrefix(s, prefix) {
+		return nil, fmt.Errorf("Fingerprint: %q is not a %q URI", s, Scheme)
+	}
+
+	parts := strings.Split(s[len(prefix):], "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Fingerprint: %q is not in form of \"hash://<algorithm>/<digest>\"", s)
+	}
+
+	if !digestPattern.MatchString(parts[1]) {
+		return nil, fmt.Errorf("Fingerprint: digest should be lower case hexadecimal")
+	}
+
+	digest, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("Fingerprint: invalid digest (%s)", err)
+	}
+
+	return New(parts[0], digest)
+}
+
+// ParseAny parses a fingerprint in any supported form: a "hash://" URI, an
+// IPFS CID or a multibase encoded multihash. As a base58 multihash is
+// indistinguishable from a CIDv0, it is always parsed as a CID.
+func ParseAny(s string) (*Fingerprint, error) {
+	if strings.HasPrefix(s, Scheme+"://") {
+		return Parse(s)
+	}
+
+	if len(s) == 46 && strings.HasPrefix(s, "Qm") {
+		c, err := cid.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("Fingerprint: invalid CID (%s)", err)
+		}
+		return FromCid(c)
+	}
+
+	_, buf, err := mbase.Decode(s)
+	if err != nil || len(buf) == 0 {
+		return nil, fmt.Errorf("Fingerprint: %q is neither a hash URI, a CID nor a multihash", s)
+	}
+
+	// A binary CIDv1 always begins with its version while a multihash begins
+	// with the hash function code
+	if buf[0] == 1 {
+		c, err := cid.Cast(buf)
+		if err != nil {
+			return nil, fmt.Errorf("Fingerprint: invalid CID (%s)", err)
+		}
+		return FromCid(c)
+	}
+
+	return FromMultihash(buf)
+}
+
+// FromCid creates a fingerprint from an IPFS CID
+func FromCid(c cid.Cid) (*Fingerprint, error) {
+	dm, err := mh.Decode(c.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("Fingerprint: invalid multihash in CID (%s)", err)
+	}
+
+	f, err := fromDecodedMultihash(dm)
+	if err != nil {
+		return nil, err
+	}
+
+	f.kind = CID
+	f.c = c
+	return f, nil
+}
+
+// FromMultihash creates a fingerprint from a multihash
+func FromMultihash(buf []byte) (*Fingerprint, error) {
+	dm, err := mh.Decode(buf)
+	if err != nil {
+		return nil, fmt.Errorf("Fingerprint: invalid multihash (%s)", err)
+	}
+
+	f, err := fromDecodedMultihash(dm)
+	if err != nil {
+		return nil, err
+	}
+
+	f.kind = Multihash
+	return f, nil
+}
+
+func fromDecodedMultihash(dm *mh.DecodedMultihash) (*Fingerprint, error) {
+	f := &Fingerprint{
+		code:   dm.Code,
+		digest: append([]byte{}, dm.Digest...),
+	}
+
+	// The hash function of a multihash may not be registered as a fingerprint
+	// algorithm, the fingerprint is still valid but cannot be verified
+	if a, ok := algorithmByCode(dm.Code); ok {
+		if len(dm.Digest) != a.Size() {
+			return nil, fmt.Errorf(
+				"Fingerprint: digest of %q should length %d but %d is found",
+				a.Name(),
+				a.Size(),
+				len(dm.Digest),
+			)
+		}
+		f.algorithm = a
+	}
+
+	return f, nil
+}
+
+// Compute computes the fingerprint of the data read from r
+func Compute(r io.Reader, algorithm string) (*Fingerprint, error) {
+	a, err := GetAlgorithm(algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	h := a.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, err
+	}
+
+	return New(a.Name(), h.Sum(nil))
+}
+
+// Kind returns the form of the fingerprint
+func (f *Fingerprint) Kind() Kind {
+	return f.kind
+}
+
+// Algorithm returns the hash algorithm of the fingerprint, nil is returned if
+// the hash function of a CID or multihash is not a registered algorithm
+func (f *Fingerprint) Algorithm() *Algorithm {
+	return f.algorithm
+}
+
+// Digest returns the digest of the fingerprint
+func (f *Fingerprint) Digest() []byte {
+	return append([]byte{}, f.digest...)
+}
+
+// IsContentDigest checks whether the digest is computed over the content
+// bytes, which is not the case for a CID of an IPLD node other than raw
+func (f *Fingerprint) IsContentDigest() bool {
+	return f.kind != CID || f.c.Type() == cid.Raw
+}
+
+// Equals checks whether two fingerprints identify the same content
+func (f *Fingerprint) Equals(o *Fingerprint) bool {
+	if !f.IsContentDigest() || !o.IsContentDigest() {
+		return f.kind == CID && o.kind == CID && f.c.Equals(o.c)
+	}
+
+	if f.algorithm != nil && o.algorithm != nil {
+		if f.algorithm.Name() != o.algorithm.Name() {
+			return false
+		}
+	} else if f.code != o.code || f.code == 0 {
+		return false
+	}
+
+	return bytes.Equal(f.digest, o.digest)
+}
+
+// String returns the fingerprint in its original form
+func (f *Fingerprint) String() string {
+	switch f.kind {
+	case CID:
+		return f.c.String()
+	case Multihash:
+		buf, err := f.Multihash()
+		if err != nil {
+			return ""
+		}
+
+		s, err := mbase.Encode(mbase.Base58BTC, buf)
+		if err != nil {
+			return ""
+		}
+		return s
+	}
+
+	return fmt.Sprintf("%s://%s/%s", Scheme, f.algorithm.Name(), hex.EncodeToString(f.digest))
+}
+
+// ToHashURI converts the fingerprint into the "hash://" form
+func (f *Fingerprint) ToHashURI() (*Fingerprint, error) {
+	if err := f.checkContentDigest(); err != nil {
+		return nil, err
+	}
+
+	if f.algorithm == nil {
+		return nil, fmt.Errorf("Fingerprint: multihash code 0x%x is not a registered algorithm", f.code)
+	}
+
+	return New(f.algorithm.Name(), f.digest)
+}
+
+// Multihash returns the fingerprint as a multihash
+func (f *Fingerprint) Multihash() (mh.Multihash, error) {
+	if err := f.checkContentDigest(); err != nil {
+		return nil, err
+	}
+
+	if f.kind == CID {
+		return f.c.Hash(), nil
+	}
+
+	if f.code == 0 {
+		return nil, fmt.Errorf("Fingerprint: %q has no multihash code", f.algorithm.Name())
+	}
+
+	return mh.Encode(f.digest, f.code)
+}
+
+// ToMultihash converts the fingerprint into the multihash form
+func (f *Fingerprint) ToMultihash() (*Fingerprint, error) {
+	buf, err := f.Multihash()
+	if err != nil {
+		return nil, err
+	}
+
+	return FromMultihash(buf)
+}
+
+// ToCid converts the fingerprint into a CIDv1 of a raw IPLD node
+func (f *Fingerprint) ToCid() (*Fingerprint, error) {
+	if f.kind == CID {
+		return f, nil
+	}
+
+	buf, err := f.Multihash()
+	if err != nil {
+		return nil, err
+	}
+
+	return FromCid(cid.NewCidV1(cid.Raw, buf))
+}
+
+// Verify computes the digest of the data read from r and compares with the fingerprint
+func (f *Fingerprint) Verify(r io.Reader) error {
+	if err := f.checkContentDigest(); err != nil {
+		return err
+	}
+
+	if f.algorithm == nil {
+		return fmt.Errorf("Fingerprint: multihash code 0x%x is not a registered algorithm", f.code)
+	}
+
+	actual, err := Compute(r, f.algorithm.Name())
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(f.digest, actual.digest) {
+		return fmt.Errorf("Fingerprint: %q is not matched: expected %q", actual, f)
+	}
+
+	return nil
+}
+
+func (f *Fingerprint) checkContentDigest() error {
+	if !f.IsContentDigest() {
+		return fmt.Errorf(
+			"Fingerprint: digest of CID with codec 0x%x is not computed over the content",
+			f.c.Type(),
+		)
+	}
+
+	return nil
+}
+
+// VerifyContent verifies the data read from r against the fingerprint of a content block
+func VerifyContent(r io.Reader, content block.IscnObject) error {
+	if content.Cid().Type() != block.CodecContent {
+		return fmt.Errorf("Fingerprint: %s is not a content block", content)
+	}
+
+	value, err := content.GetString(ContentKey)
+	if err != nil {
+		return err
+	}
+
+	f, err := ParseAny(value)
+	if err != nil {
+		return err
+	}
+
+	return f.Verify(r)
+}
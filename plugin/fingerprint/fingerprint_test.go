@@ -0,0 +1,228 @@
+package fingerprint_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+	"github.com/likecoin/iscn-ipld/plugin/fingerprint"
+
+	mbase "github.com/multiformats/go-multibase"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// The SHA-256 digest of "hello"
+const helloSha256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func TestParse(t *testing.T) {
+	f, err := fingerprint.Parse("hash://sha256/" + helloSha256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Kind() != fingerprint.HashURI || f.Algorithm().Name() != "sha256" || f.String() != "hash://sha256/"+helloSha256 {
+		t.Errorf("Parse: %s (%s) is found", f, f.Kind())
+	}
+
+	cases := []string{
+		"sha256/" + helloSha256,
+		"https://sha256/" + helloSha256,
+		"hash://sha256",
+		"hash://sha256/" + helloSha256 + "/x",
+		"hash://sha256/" + strings.ToUpper(helloSha256),
+		"hash://sha256/" + helloSha256[:62],
+		"hash://sha256/" + helloSha256[:63],
+		"hash://sha512/" + helloSha256,
+	}
+
+	for _, s := range cases {
+		if _, err := fingerprint.Parse(s); err == nil {
+			t.Errorf("Parse(%q): error is expected", s)
+		}
+	}
+
+	_, err = fingerprint.Parse("hash://unknown-hash/" + helloSha256)
+	if !errors.Is(err, fingerprint.ErrUnknownAlgorithm) {
+		t.Errorf("Parse: ErrUnknownAlgorithm is expected but %v is found", err)
+	}
+}
+
+func TestComputeAndVerify(t *testing.T) {
+	for _, algorithm := range fingerprint.Algorithms() {
+		f, err := fingerprint.Compute(strings.NewReader("hello"), algorithm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if algorithm == "sha256" && f.String() != "hash://sha256/"+helloSha256 {
+			t.Errorf("Compute: hash://sha256/%s is expected but %s is found", helloSha256, f)
+		}
+
+		if err := f.Verify(strings.NewReader("hello")); err != nil {
+			t.Errorf("%s: %s", algorithm, err)
+		}
+
+		if err := f.Verify(strings.NewReader("hello!")); err == nil {
+			t.Errorf("%s: the other content is verified", algorithm)
+		}
+	}
+
+	if _, err := fingerprint.Compute(strings.NewReader("hello"), "unknown-hash"); err == nil {
+		t.Errorf("Compute: the unknown algorithm is accepted")
+	}
+}
+
+func TestVerifyContent(t *testing.T) {
+	r := block.NewRegistry()
+	content.RegisterTo(r)
+
+	obj, err := r.Encode(block.CodecContent, 5, map[string]interface{}{
+		"version":     uint64(1),
+		"type":        "article",
+		"fingerprint": "hash://sha256/" + helloSha256,
+		"title":       "Title",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fingerprint.VerifyContent(bytes.NewBufferString("hello"), obj); err != nil {
+		t.Error(err)
+	}
+
+	if err := fingerprint.VerifyContent(bytes.NewBufferString("hello!"), obj); err == nil {
+		t.Errorf("VerifyContent: the other content is verified")
+	}
+}
+
+func TestParseAny(t *testing.T) {
+	uri, err := fingerprint.Parse("hash://sha256/" + helloSha256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := uri.Multihash()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := cid.NewCidV1(cid.Raw, buf)
+	raw58, err := raw.StringOfBase(mbase.Base58BTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	multihash, err := mbase.Encode(mbase.Base32, buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		s       string
+		kind    fingerprint.Kind
+		content bool
+	}{
+		{uri.String(), fingerprint.HashURI, true},
+		{raw.String(), fingerprint.CID, true},
+		{raw58, fingerprint.CID, true},
+		{cid.NewCidV0(buf).String(), fingerprint.CID, false},
+		{cid.NewCidV1(cid.DagCBOR, buf).String(), fingerprint.CID, false},
+		{multihash, fingerprint.Multihash, true},
+	}
+
+	for _, c := range cases {
+		f, err := fingerprint.ParseAny(c.s)
+		if err != nil {
+			t.Errorf("ParseAny(%q): %s", c.s, err)
+			continue
+		}
+
+		if f.Kind() != c.kind || f.IsContentDigest() != c.content {
+			t.Errorf("ParseAny(%q): %s (%v) is found", c.s, f.Kind(), f.IsContentDigest())
+			continue
+		}
+
+		if !c.content {
+			if err := f.Verify(strings.NewReader("hello")); err == nil {
+				t.Errorf("%q: the digest of an IPLD node is verified as the content", c.s)
+			}
+			continue
+		}
+
+		if !f.Equals(uri) {
+			t.Errorf("%q does not equal %s", c.s, uri)
+		}
+
+		if err := f.Verify(strings.NewReader("hello")); err != nil {
+			t.Errorf("%q: %s", c.s, err)
+		}
+
+		for _, convert := range []func() (*fingerprint.Fingerprint, error){f.ToHashURI, f.ToCid, f.ToMultihash} {
+			converted, err := convert()
+			if err != nil {
+				t.Errorf("%q: %s", c.s, err)
+			} else if !converted.Equals(uri) {
+				t.Errorf("%q is converted to %s", c.s, converted)
+			}
+		}
+	}
+
+	for _, s := range []string{"", "hello", "zzzz", "Qm" + strings.Repeat("1", 44)} {
+		if _, err := fingerprint.ParseAny(s); err == nil {
+			t.Errorf("ParseAny(%q): error is expected", s)
+		}
+	}
+}
+
+func TestFromMultihash(t *testing.T) {
+	// A hash function which is not a registered algorithm is accepted but
+	// cannot be verified
+	sha1, err := mh.Sum([]byte("hello"), mh.SHA1, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := fingerprint.FromMultihash(sha1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Algorithm() != nil {
+		t.Errorf("FromMultihash: %s is not a registered algorithm", f.Algorithm().Name())
+	}
+
+	if err := f.Verify(strings.NewReader("hello")); err == nil {
+		t.Errorf("Verify: the digest of an unknown algorithm is verified")
+	}
+
+	if _, err := f.ToHashURI(); err == nil {
+		t.Errorf("ToHashURI: the unknown algorithm is converted")
+	}
+
+	fromCid, err := fingerprint.FromCid(cid.NewCidV1(cid.Raw, sha1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !fromCid.Equals(f) {
+		t.Errorf("%s does not equal %s", fromCid, f)
+	}
+
+	// The digest length of a registered algorithm is checked
+	short, err := mh.Encode(make([]byte, 16), mh.SHA2_256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fingerprint.FromMultihash(short); err == nil {
+		t.Errorf("FromMultihash: the short digest is accepted")
+	}
+
+	if _, err := fingerprint.FromMultihash([]byte{0x12}); err == nil {
+		t.Errorf("FromMultihash: the invalid multihash is accepted")
+	}
+}
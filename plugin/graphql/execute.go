@@ -0,0 +1,353 @@
+package graphql
+
+import (
+	"fmt"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"gitlab.com/c0b/go-ordered-json"
+
+	node "github.com/ipfs/go-ipld-format"
+)
+
+// typenameField is the meta field of the name of the object type
+const typenameField = "__typename"
+
+// MaxLoads is the maximum number of distinct blocks loaded by a request, the
+// blocks linked more than once are loaded once
+const MaxLoads = 1000
+
+// ==================================================
+// Response
+// ==================================================
+
+// Error is an error of the GraphQL response
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Response is the GraphQL response
+type Response struct {
+	Data   *ordered.OrderedMap `json:"data"`
+	Errors []*Error            `json:"errors,omitempty"`
+}
+
+// ==================================================
+// Executor
+// ==================================================
+
+type executor struct {
+	schema    *Schema
+	loader    block.Loader
+	fragments map[string]*fragment
+	variables map[string]interface{}
+	errors    []*Error
+
+	// blocks are the blocks loaded by the request
+	blocks map[cid.Cid]block.IscnObject
+}
+
+// Execute executes the query with the variables, objects are loaded by the
+// loader
+func (s *Schema) Execute(
+	loader block.Loader,
+	query string,
+	operationName string,
+	variables map[string]interface{},
+) *Response {
+	doc, err := parse(query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	var op *operation
+	for _, o := range doc.operations {
+		if operationName == "" || o.name == operationName {
+			if op != nil {
+				return &Response{Errors: []*Error{{
+					Message: "GraphQL: operation name is required for multiple operations",
+				}}}
+			}
+			op = o
+		}
+	}
+
+	if op == nil {
+		return &Response{Errors: []*Error{{
+			Message: fmt.Sprintf("GraphQL: unknown operation %q", operationName),
+		}}}
+	}
+
+	e := &executor{
+		schema:    s,
+		loader:    loader,
+		fragments: doc.fragments,
+		variables: map[string]interface{}{},
+		errors:    []*Error{},
+		blocks:    map[cid.Cid]block.IscnObject{},
+	}
+
+	for name, value := range op.variables {
+		e.variables[name] = value
+	}
+	for name, value := range variables {
+		e.variables[name] = value
+	}
+
+	data := ordered.NewOrderedMap()
+	for _, f := range e.collectFields(op.selectionSet, "Query") {
+		key := f.responseKey()
+		path := []interface{}{key}
+
+		if f.name == typenameField {
+			data.Set(key, "Query")
+			continue
+		}
+
+		typ, ok := s.queries[f.name]
+		if !ok {
+			e.errorf(path, "unknown field %q on type \"Query\"", f.name)
+			continue
+		}
+
+		data.Set(key, e.executeQuery(f, typ, path))
+	}
+
+	res := &Response{Data: data}
+	if len(e.errors) > 0 {
+		res.Errors = e.errors
+	}
+	return res
+}
+
+func (e *executor) errorf(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, &Error{
+		Message: fmt.Sprintf("GraphQL: "+format, args...),
+		Path:    path,
+	})
+}
+
+// collectFields flattens the fragments of the selection set for the type
+func (e *executor) collectFields(selectionSet []selection, typeName string) []*fieldSelection {
+	return e.collectFieldsOf(selectionSet, typeName, map[string]bool{})
+}
+
+// collectFieldsOf spreads each fragment once, so a fragment spreading itself
+// is not followed even if the document is not validated
+func (e *executor) collectFieldsOf(
+	selectionSet []selection,
+	typeName string,
+	visited map[string]bool,
+) []*fieldSelection {
+	res := []*fieldSelection{}
+	for _, s := range selectionSet {
+		switch {
+		case s.field != nil:
+			res = append(res, s.field)
+		case s.inlineFragment != nil:
+			if s.inlineFragment.typeCondition == "" || s.inlineFragment.typeCondition == typeName {
+				res = append(res, e.collectFieldsOf(s.inlineFragment.selectionSet, typeName, visited)...)
+			}
+		default:
+			if visited[s.fragmentSpread] {
+				continue
+			}
+			visited[s.fragmentSpread] = true
+
+			f, ok := e.fragments[s.fragmentSpread]
+			if !ok {
+				e.errorf(nil, "unknown fragment %q", s.fragmentSpread)
+				continue
+			}
+
+			if f.typeCondition == typeName {
+				res = append(res, e.collectFieldsOf(f.selectionSet, typeName, visited)...)
+			}
+		}
+	}
+	return res
+}
+
+// argument returns the value of the argument with the variables substituted
+func (e *executor) argument(f *fieldSelection, name string) (interface{}, bool) {
+	value, ok := f.arguments[name]
+	if !ok {
+		return nil, false
+	}
+
+	if v, ok := value.(variable); ok {
+		value, ok = e.variables[string(v)]
+		return value, ok
+	}
+	return value, true
+}
+
+func (e *executor) executeQuery(f *fieldSelection, typ *objectType, path []interface{}) interface{} {
+	arg, ok := e.argument(f, "cid")
+	if !ok || arg == nil {
+		e.errorf(path, "argument \"cid\" is required")
+		return nil
+	}
+
+	s, ok := arg.(string)
+	if !ok {
+		e.errorf(path, "argument \"cid\" should be a string")
+		return nil
+	}
+
+	c, err := cid.Decode(s)
+	if err != nil {
+		e.errorf(path, "invalid CID %q: %s", s, err)
+		return nil
+	}
+
+	return e.executeBlock(c, f.selectionSet, typ, path)
+}
+
+// executeBlock loads the block and executes the selection set on it
+func (e *executor) executeBlock(
+	c cid.Cid,
+	selectionSet []selection,
+	typ *objectType,
+	path []interface{},
+) interface{} {
+	if typ.codec != 0 && c.Type() != typ.codec {
+		e.errorf(path, "%s is not a %s", c, typ.name)
+		return nil
+	}
+
+	obj, err := e.load(c)
+	if err != nil {
+		e.errorf(path, "%s", err)
+		return nil
+	}
+
+	return e.executeObject(obj, selectionSet, typ, path)
+}
+
+// load loads the block once per request, up to MaxLoads blocks
+func (e *executor) load(c cid.Cid) (block.IscnObject, error) {
+	if obj, ok := e.blocks[c]; ok {
+		return obj, nil
+	}
+
+	if len(e.blocks) >= MaxLoads {
+		return nil, fmt.Errorf("more than %d blocks are loaded", MaxLoads)
+	}
+
+	obj, err := e.loader.Load(c)
+	if err != nil {
+		return nil, err
+	}
+
+	e.blocks[c] = obj
+	return obj, nil
+}
+
+func (e *executor) executeObject(
+	obj node.Resolver,
+	selectionSet []selection,
+	typ *objectType,
+	path []interface{},
+) interface{} {
+	if len(selectionSet) == 0 {
+		e.errorf(path, "selection set is required for type %q", typ.name)
+		return nil
+	}
+
+	res := ordered.NewOrderedMap()
+	for _, f := range e.collectFields(selectionSet, typ.name) {
+		key := f.responseKey()
+		fieldPath := append(append([]interface{}{}, path...), key)
+
+		if f.name == typenameField {
+			res.Set(key, typ.name)
+			continue
+		}
+
+		def, ok := typ.byName[f.name]
+		if !ok {
+			e.errorf(fieldPath, "unknown field %q on type %q", f.name, typ.name)
+			continue
+		}
+
+		if f.name == cidField {
+			if o, ok := obj.(block.IscnObject); ok {
+				res.Set(key, o.Cid().String())
+			} else {
+				res.Set(key, nil)
+			}
+			continue
+		}
+
+		// Absent fields are null
+		value, _, err := obj.Resolve([]string{f.name})
+		if err != nil {
+			res.Set(key, nil)
+			continue
+		}
+
+		res.Set(key, e.completeValue(value, f, def.typ, fieldPath))
+	}
+
+	return res
+}
+
+// completeValue converts the resolved value to the value of the type
+func (e *executor) completeValue(
+	value interface{},
+	f *fieldSelection,
+	typ *typeRef,
+	path []interface{},
+) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	if typ.elem != nil {
+		list, ok := value.([]interface{})
+		if !ok {
+			e.errorf(path, "list is expected but '%T' is found", value)
+			return nil
+		}
+
+		res := []interface{}{}
+		for i, elem := range list {
+			elemPath := append(append([]interface{}{}, path...), i)
+			res = append(res, e.completeValue(elem, f, typ.elem, elemPath))
+		}
+		return res
+	}
+
+	if objType, ok := e.schema.types[typ.name]; ok {
+		switch v := value.(type) {
+		case *node.Link:
+			return e.executeBlock(v.Cid, f.selectionSet, objType, path)
+		case node.Resolver:
+			return e.executeObject(v, f.selectionSet, objType, path)
+		}
+
+		e.errorf(path, "object is expected but '%T' is found", value)
+		return nil
+	}
+
+	if len(f.selectionSet) != 0 {
+		e.errorf(path, "selection set is not allowed for scalar %q", typ.name)
+		return nil
+	}
+
+	return serializeScalar(value)
+}
+
+// serializeScalar converts the scalar value to JSON value, links are
+// presented as CID strings
+func serializeScalar(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *node.Link:
+		return v.Cid.String()
+	case cid.Cid:
+		return v.String()
+	}
+	return value
+}
@@ -0,0 +1,93 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/iscn"
+
+	mh "github.com/multiformats/go-multihash"
+)
+
+// newRegistry returns a registry with all ISCN objects registered
+func newRegistry() *block.Registry {
+	r := block.NewRegistry()
+	iscn.RegisterTo(r)
+	return r
+}
+
+func TestExecuteLoadsBlocksOnce(t *testing.T) {
+	r := newRegistry()
+	obj, err := r.Encode(block.CodecContent, 5, map[string]interface{}{
+		"version":     uint64(1),
+		"type":        "article",
+		"fingerprint": "hash://sha256/9564b85669d5e96ac969dd0161b8475bbced9e5999c6ec598da718a3045d6f2e",
+		"title":       "Title",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loads := 0
+	loader := block.LoaderFunc(func(c cid.Cid) (block.IscnObject, error) {
+		loads++
+		return obj, nil
+	})
+
+	s, err := NewSchema(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := `query($c: String) { a: content(cid: $c) { type } b: content(cid: $c) { ...F } }
+		fragment F on Content { version }`
+	res := s.Execute(loader, query, "", map[string]interface{}{"c": obj.Cid().String()})
+	if len(res.Errors) != 0 {
+		t.Fatal(res.Errors[0].Message)
+	}
+
+	if loads != 1 {
+		t.Errorf("The block is loaded %d times", loads)
+	}
+}
+
+func TestLoadLimit(t *testing.T) {
+	loads := 0
+	e := &executor{
+		loader: block.LoaderFunc(func(c cid.Cid) (block.IscnObject, error) {
+			loads++
+			return nil, nil
+		}),
+		blocks: map[cid.Cid]block.IscnObject{},
+	}
+
+	cids := []cid.Cid{}
+	for i := 0; i <= MaxLoads; i++ {
+		h, err := mh.Sum([]byte{byte(i), byte(i >> 8)}, mh.SHA2_256, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cids = append(cids, cid.NewCidV1(block.CodecContent, h))
+	}
+
+	for _, c := range cids[:MaxLoads] {
+		if _, err := e.load(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Loaded blocks are not loaded again nor counted
+	if _, err := e.load(cids[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := e.load(cids[MaxLoads]); err == nil || !strings.Contains(err.Error(), "blocks are loaded") {
+		t.Errorf("load: limit error is expected but %v is found", err)
+	}
+
+	if loads != MaxLoads {
+		t.Errorf("%d loads are expected but %d is found", MaxLoads, loads)
+	}
+}
@@ -0,0 +1,118 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// MaxQuerySize is the maximum size of the request body
+const MaxQuerySize = 1 << 20
+
+// request is the body of a GraphQL request
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Handler serves GraphQL queries over HTTP:
+//
+//	POST {"query": ..., "operationName": ..., "variables": ...}
+//	GET  ?query=...&operationName=...&variables=...
+//
+// A GET request without query returns the schema in SDL
+type Handler struct {
+	registry *block.Registry
+	loader   block.Loader
+
+	once   sync.Once
+	schema *Schema
+	err    error
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler creates a GraphQL handler, objects are loaded by the loader.
+// The schema is generated from the ISCN schemas registered to the registry
+// on first use
+func NewHandler(r *block.Registry, loader block.Loader) *Handler {
+	return &Handler{
+		registry: r,
+		loader:   loader,
+	}
+}
+
+// Schema returns the GraphQL schema
+func (h *Handler) Schema() (*Schema, error) {
+	h.once.Do(func() {
+		h.schema, h.err = NewSchema(h.registry)
+	})
+	return h.schema, h.err
+}
+
+// ServeHTTP serves the GraphQL request
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	schema, err := h.Schema()
+	if err != nil {
+		writeResponse(w, http.StatusInternalServerError, &Response{
+			Errors: []*Error{{Message: err.Error()}},
+		})
+		return
+	}
+
+	req := request{}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+
+		if req.Query == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(schema.SDL()))
+			return
+		}
+
+		if v := query.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				writeResponse(w, http.StatusBadRequest, &Response{
+					Errors: []*Error{{Message: fmt.Sprintf("Invalid variables: %s", err)}},
+				})
+				return
+			}
+		}
+	case http.MethodPost:
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxQuerySize))
+		if err := decoder.Decode(&req); err != nil {
+			writeResponse(w, http.StatusBadRequest, &Response{
+				Errors: []*Error{{Message: fmt.Sprintf("Invalid JSON: %s", err)}},
+			})
+			return
+		}
+	default:
+		writeResponse(w, http.StatusMethodNotAllowed, &Response{
+			Errors: []*Error{{Message: fmt.Sprintf("Method %s is not allowed", r.Method)}},
+		})
+		return
+	}
+
+	res := schema.Execute(h.loader, req.Query, req.OperationName, req.Variables)
+
+	status := http.StatusOK
+	if res.Data == nil {
+		// The query is not executed
+		status = http.StatusBadRequest
+	}
+	writeResponse(w, status, res)
+}
+
+func writeResponse(w http.ResponseWriter, status int, res *Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(res)
+}
@@ -0,0 +1,791 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ==================================================
+// Document
+// ==================================================
+
+// The parser supports the subset of GraphQL needed for querying ISCN records:
+// query operations with variables, fields with aliases and arguments, named
+// and inline fragments. Mutations, subscriptions and directives are rejected.
+
+// MaxDepth is the maximum nesting of the selection sets, values and types in
+// a document, the selection sets of the fragment spreads are counted where
+// they are spread
+const MaxDepth = 32
+
+// MaxFields is the maximum number of fields selected by an operation, the
+// fields of the fragments are counted every time they are spread
+const MaxFields = 1000
+
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	name         string
+	variables    map[string]interface{}
+	selectionSet []selection
+}
+
+type fragment struct {
+	name          string
+	typeCondition string
+	selectionSet  []selection
+}
+
+// selection is a field, a fragment spread or an inline fragment
+type selection struct {
+	field *fieldSelection
+
+	// fragmentSpread is the name of the fragment spread
+	fragmentSpread string
+
+	// inlineFragment is set for an inline fragment
+	inlineFragment *fragment
+}
+
+type fieldSelection struct {
+	alias        string
+	name         string
+	arguments    map[string]interface{}
+	selectionSet []selection
+}
+
+// variable is a reference to a variable in the arguments
+type variable string
+
+func (f *fieldSelection) responseKey() string {
+	if f.alias != "" {
+		return f.alias
+	}
+	return f.name
+}
+
+// ==================================================
+// Parser
+// ==================================================
+
+type parser struct {
+	lexer *lexer
+	tok   token
+	depth int
+}
+
+func parse(source string) (*document, error) {
+	p := &parser{lexer: &lexer{source: source}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{
+		operations: []*operation{},
+		fragments:  map[string]*fragment{},
+	}
+
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.tok.is(tokenPunct, "{"):
+			selectionSet, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{selectionSet: selectionSet})
+		case p.tok.is(tokenName, "query"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case p.tok.is(tokenName, "fragment"):
+			f, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			doc.fragments[f.name] = f
+		case p.tok.is(tokenName, "mutation"), p.tok.is(tokenName, "subscription"):
+			return nil, p.errorf("%s is not supported", p.tok.text)
+		default:
+			return nil, p.errorf("unexpected %s", p.tok)
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("GraphQL: no operation in the document")
+	}
+
+	if err := doc.validate(); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("GraphQL: syntax error at position %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+	return nil
+}
+
+// enter enters a nested selection set, value or type, which should be left by
+// calling leave
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > MaxDepth {
+		return p.errorf("the document is nested more than %d levels", MaxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if !p.tok.is(kind, text) {
+		return p.errorf("%q is expected but %s is found", text, p.tok)
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokenName {
+		return "", p.errorf("name is expected but %s is found", p.tok)
+	}
+
+	name := p.tok.text
+	return name, p.advance()
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	// Skip "query"
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	op := &operation{
+		variables: map[string]interface{}{},
+	}
+
+	if p.tok.kind == tokenName {
+		op.name = p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.tok.is(tokenPunct, "(") {
+		if err := p.parseVariableDefinitions(op); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.tok.is(tokenPunct, "@") {
+		return nil, p.errorf("directives are not supported")
+	}
+
+	selectionSet, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	op.selectionSet = selectionSet
+	return op, nil
+}
+
+// parseVariableDefinitions parses the variables and their default values,
+// the types are not checked
+func (p *parser) parseVariableDefinitions(op *operation) error {
+	if err := p.expect(tokenPunct, "("); err != nil {
+		return err
+	}
+
+	for !p.tok.is(tokenPunct, ")") {
+		if err := p.expect(tokenPunct, "$"); err != nil {
+			return err
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+
+		if err := p.expect(tokenPunct, ":"); err != nil {
+			return err
+		}
+
+		if err := p.skipType(); err != nil {
+			return err
+		}
+
+		var defaultValue interface{}
+		if p.tok.is(tokenPunct, "=") {
+			if err := p.advance(); err != nil {
+				return err
+			}
+
+			defaultValue, err = p.parseValue(true)
+			if err != nil {
+				return err
+			}
+		}
+		op.variables[name] = defaultValue
+	}
+
+	return p.advance()
+}
+
+func (p *parser) skipType() error {
+	if err := p.enter(); err != nil {
+		return err
+	}
+	defer p.leave()
+
+	if p.tok.is(tokenPunct, "[") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+
+		if err := p.skipType(); err != nil {
+			return err
+		}
+
+		if err := p.expect(tokenPunct, "]"); err != nil {
+			return err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return err
+	}
+
+	if p.tok.is(tokenPunct, "!") {
+		return p.advance()
+	}
+	return nil
+}
+
+func (p *parser) parseFragment() (*fragment, error) {
+	// Skip "fragment"
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	selectionSet, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return &fragment{
+		name:          name,
+		typeCondition: typeCondition,
+		selectionSet:  selectionSet,
+	}, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if err := p.expect(tokenPunct, "{"); err != nil {
+		return nil, err
+	}
+
+	selections := []selection{}
+	for !p.tok.is(tokenPunct, "}") {
+		if p.tok.kind == tokenEOF {
+			return nil, p.errorf("unterminated selection set")
+		}
+
+		s, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, s)
+	}
+
+	return selections, p.advance()
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	if p.tok.is(tokenPunct, "...") {
+		if err := p.advance(); err != nil {
+			return selection{}, err
+		}
+
+		if p.tok.is(tokenName, "on") || p.tok.is(tokenPunct, "{") {
+			f := &fragment{}
+			if p.tok.is(tokenName, "on") {
+				if err := p.advance(); err != nil {
+					return selection{}, err
+				}
+
+				typeCondition, err := p.expectName()
+				if err != nil {
+					return selection{}, err
+				}
+				f.typeCondition = typeCondition
+			}
+
+			selectionSet, err := p.parseSelectionSet()
+			if err != nil {
+				return selection{}, err
+			}
+			f.selectionSet = selectionSet
+
+			return selection{inlineFragment: f}, nil
+		}
+
+		name, err := p.expectName()
+		if err != nil {
+			return selection{}, err
+		}
+		return selection{fragmentSpread: name}, nil
+	}
+
+	f := &fieldSelection{
+		arguments: map[string]interface{}{},
+	}
+
+	name, err := p.expectName()
+	if err != nil {
+		return selection{}, err
+	}
+
+	if p.tok.is(tokenPunct, ":") {
+		if err := p.advance(); err != nil {
+			return selection{}, err
+		}
+
+		f.alias = name
+		name, err = p.expectName()
+		if err != nil {
+			return selection{}, err
+		}
+	}
+	f.name = name
+
+	if p.tok.is(tokenPunct, "(") {
+		if err := p.advance(); err != nil {
+			return selection{}, err
+		}
+
+		for !p.tok.is(tokenPunct, ")") {
+			argName, err := p.expectName()
+			if err != nil {
+				return selection{}, err
+			}
+
+			if err := p.expect(tokenPunct, ":"); err != nil {
+				return selection{}, err
+			}
+
+			value, err := p.parseValue(false)
+			if err != nil {
+				return selection{}, err
+			}
+			f.arguments[argName] = value
+		}
+
+		if err := p.advance(); err != nil {
+			return selection{}, err
+		}
+	}
+
+	if p.tok.is(tokenPunct, "@") {
+		return selection{}, p.errorf("directives are not supported")
+	}
+
+	if p.tok.is(tokenPunct, "{") {
+		selectionSet, err := p.parseSelectionSet()
+		if err != nil {
+			return selection{}, err
+		}
+		f.selectionSet = selectionSet
+	}
+
+	return selection{field: f}, nil
+}
+
+func (p *parser) parseValue(isConst bool) (interface{}, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	tok := p.tok
+	switch tok.kind {
+	case tokenString:
+		return tok.text, p.advance()
+	case tokenInt:
+		i, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid integer %q", tok.text)
+		}
+		return i, p.advance()
+	case tokenFloat:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid float %q", tok.text)
+		}
+		return f, p.advance()
+	case tokenName:
+		switch tok.text {
+		case "true":
+			return true, p.advance()
+		case "false":
+			return false, p.advance()
+		case "null":
+			return nil, p.advance()
+		}
+		// Enum value
+		return tok.text, p.advance()
+	case tokenPunct:
+		switch tok.text {
+		case "$":
+			if isConst {
+				return nil, p.errorf("variable is not allowed")
+			}
+
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			return variable(name), nil
+		case "[":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			list := []interface{}{}
+			for !p.tok.is(tokenPunct, "]") {
+				value, err := p.parseValue(isConst)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			return list, p.advance()
+		case "{":
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+
+			obj := map[string]interface{}{}
+			for !p.tok.is(tokenPunct, "}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+
+				if err := p.expect(tokenPunct, ":"); err != nil {
+					return nil, err
+				}
+
+				value, err := p.parseValue(isConst)
+				if err != nil {
+					return nil, err
+				}
+				obj[name] = value
+			}
+			return obj, p.advance()
+		}
+	}
+
+	return nil, p.errorf("value is expected but %s is found", tok)
+}
+
+// ==================================================
+// Validation
+// ==================================================
+
+// validate rejects the fragments spreading themselves directly or through
+// other fragments (GraphQL spec 5.5.2.2) and the operations nested more than
+// MaxDepth levels or selecting more than MaxFields fields after the fragments
+// are spread
+func (doc *document) validate() error {
+	v := &validator{
+		doc:       doc,
+		fragments: map[string]*cost{},
+		visiting:  map[string]bool{},
+	}
+
+	for name := range doc.fragments {
+		if _, err := v.fragmentCost(name); err != nil {
+			return err
+		}
+	}
+
+	for _, op := range doc.operations {
+		c, err := v.cost(op.selectionSet)
+		if err != nil {
+			return err
+		}
+
+		if err := c.check("the operation"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cost is the nesting and the number of fields of a selection set
+type cost struct {
+	depth  int
+	fields int
+}
+
+// add adds the fields, which are counted up to MaxFields+1 so that they do
+// not overflow
+func (c *cost) add(fields int) {
+	c.fields += fields
+	if c.fields > MaxFields {
+		c.fields = MaxFields + 1
+	}
+}
+
+func (c *cost) check(name string) error {
+	if c.depth > MaxDepth {
+		return fmt.Errorf("GraphQL: %s is nested more than %d levels", name, MaxDepth)
+	}
+
+	if c.fields > MaxFields {
+		return fmt.Errorf("GraphQL: %s selects more than %d fields", name, MaxFields)
+	}
+
+	return nil
+}
+
+type validator struct {
+	doc *document
+
+	// fragments are the costs of the validated fragments
+	fragments map[string]*cost
+
+	// visiting are the fragments being validated, spreading one of them
+	// again forms a cycle
+	visiting map[string]bool
+}
+
+// cost returns the nesting and the number of the fields in the selection set
+func (v *validator) cost(selectionSet []selection) (*cost, error) {
+	res := &cost{}
+	for _, s := range selectionSet {
+		var c *cost
+		var err error
+		switch {
+		case s.field != nil:
+			c, err = v.cost(s.field.selectionSet)
+			if err == nil {
+				c.depth++
+				c.add(1)
+			}
+		case s.inlineFragment != nil:
+			c, err = v.cost(s.inlineFragment.selectionSet)
+		default:
+			c, err = v.fragmentCost(s.fragmentSpread)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if c.depth > res.depth {
+			res.depth = c.depth
+		}
+		res.add(c.fields)
+	}
+	return res, nil
+}
+
+func (v *validator) fragmentCost(name string) (*cost, error) {
+	if c, ok := v.fragments[name]; ok {
+		return &cost{depth: c.depth, fields: c.fields}, nil
+	}
+
+	f, ok := v.doc.fragments[name]
+	if !ok {
+		// Unknown fragments are reported on execution
+		return &cost{}, nil
+	}
+
+	if v.visiting[name] {
+		return nil, fmt.Errorf("GraphQL: fragment %q spreads itself", name)
+	}
+
+	v.visiting[name] = true
+	c, err := v.cost(f.selectionSet)
+	delete(v.visiting, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.check(fmt.Sprintf("fragment %q", name)); err != nil {
+		return nil, err
+	}
+
+	v.fragments[name] = c
+	return &cost{depth: c.depth, fields: c.fields}, nil
+}
+
+// ==================================================
+// Lexer
+// ==================================================
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of document"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type lexer struct {
+	source string
+	pos    int
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func (l *lexer) next() (token, error) {
+	// Skip ignored tokens
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		if c == '#' {
+			for l.pos < len(l.source) && l.source[l.pos] != '\n' {
+				l.pos++
+			}
+			continue
+		}
+
+		if c != ' ' && c != '\t' && c != '\n' && c != '\r' && c != ',' {
+			break
+		}
+		l.pos++
+	}
+
+	if l.pos >= len(l.source) {
+		return token{kind: tokenEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.source[l.pos]
+	switch {
+	case strings.HasPrefix(l.source[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunct, text: "...", pos: start}, nil
+	case strings.IndexByte("!$():=@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: tokenPunct, text: string(c), pos: start}, nil
+	case isNameStart(c):
+		for l.pos < len(l.source) && (isNameStart(l.source[l.pos]) || isDigit(l.source[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, text: l.source[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		l.pos++
+		kind := tokenInt
+		for l.pos < len(l.source) {
+			c := l.source[l.pos]
+			if c == '.' || c == 'e' || c == 'E' || ((c == '+' || c == '-') && kind == tokenFloat) {
+				kind = tokenFloat
+			} else if !isDigit(c) {
+				break
+			}
+			l.pos++
+		}
+		return token{kind: kind, text: l.source[start:l.pos], pos: start}, nil
+	case c == '"':
+		if strings.HasPrefix(l.source[l.pos:], `"""`) {
+			end := strings.Index(l.source[l.pos+3:], `"""`)
+			if end < 0 {
+				return token{}, fmt.Errorf("GraphQL: unterminated string at position %d", start)
+			}
+			l.pos += end + 6
+			return token{kind: tokenString, text: l.source[start+3 : l.pos-3], pos: start}, nil
+		}
+
+		l.pos++
+		for l.pos < len(l.source) && l.source[l.pos] != '"' && l.source[l.pos] != '\n' {
+			if l.source[l.pos] == '\\' {
+				l.pos++
+			}
+			l.pos++
+		}
+
+		if l.pos >= len(l.source) || l.source[l.pos] != '"' {
+			return token{}, fmt.Errorf("GraphQL: unterminated string at position %d", start)
+		}
+		l.pos++
+
+		value, err := strconv.Unquote(l.source[start:l.pos])
+		if err != nil {
+			return token{}, fmt.Errorf("GraphQL: invalid string at position %d", start)
+		}
+		return token{kind: tokenString, text: value, pos: start}, nil
+	}
+
+	return token{}, fmt.Errorf("GraphQL: unexpected character %q at position %d", c, start)
+}
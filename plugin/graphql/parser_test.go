@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseRejectsFragmentCycles(t *testing.T) {
+	cases := []string{
+		`query { ...F } fragment F on Query { ...F }`,
+		`query { ...A } fragment A on Query { ...B } fragment B on Query { ...A }`,
+		`query { x } fragment A on Kernel { content { ... on Content { ...A } } }`,
+	}
+
+	for _, query := range cases {
+		if _, err := parse(query); err == nil || !strings.Contains(err.Error(), "spreads itself") {
+			t.Errorf("parse(%q): fragment cycle is expected but %v is found", query, err)
+		}
+	}
+}
+
+func TestParseRejectsDeepDocuments(t *testing.T) {
+	nested := func(open, close string, n int) string {
+		return strings.Repeat(open, n) + strings.Repeat(close, n)
+	}
+
+	cases := []string{
+		"query " + nested("{ a ", "} ", MaxDepth+1),
+		"query { a(x: " + nested("[", "]", MaxDepth+1) + ") }",
+		"query " + nested("{ ... on Query ", "} ", MaxDepth+1),
+	}
+
+	// Fragments spread within each other MaxDepth+1 levels deep without a
+	// nested selection set in any of them exceeding the limit
+	chain := "query { ...F0 }"
+	for i := 0; i <= MaxDepth; i++ {
+		chain += " fragment F" + strconv.Itoa(i) + " on Query { a { ...F" + strconv.Itoa(i+1) + " } }"
+	}
+	chain += " fragment F" + strconv.Itoa(MaxDepth+1) + " on Query { a }"
+	cases = append(cases, chain)
+
+	for _, query := range cases {
+		if _, err := parse(query); err == nil || !strings.Contains(err.Error(), "nested more than") {
+			t.Errorf("parse(%.40q...): nesting error is expected but %v is found", query, err)
+		}
+	}
+
+	if _, err := parse("query " + nested("{ a ", "} ", MaxDepth)); err != nil {
+		t.Errorf("parse: %d levels should be accepted but %s", MaxDepth, err)
+	}
+}
+
+func TestCollectFieldsSpreadsFragmentsOnce(t *testing.T) {
+	f := &fragment{name: "F", typeCondition: "Query"}
+	f.selectionSet = []selection{
+		{field: &fieldSelection{name: "a"}},
+		{fragmentSpread: "F"},
+	}
+
+	e := &executor{fragments: map[string]*fragment{"F": f}}
+	fields := e.collectFields([]selection{{fragmentSpread: "F"}, {fragmentSpread: "F"}}, "Query")
+	if len(fields) != 1 || fields[0].name != "a" {
+		t.Errorf("collectFields: one field is expected but %d is found", len(fields))
+	}
+}
+
+func TestParseRejectsWideDocuments(t *testing.T) {
+	// Each fragment spreads the next one twice under aliases, so the fields
+	// double at every level while the document stays small
+	fanOut := "query { ...F0 }"
+	for i := 0; i < 12; i++ {
+		next := "...F" + strconv.Itoa(i+1)
+		fanOut += " fragment F" + strconv.Itoa(i) + " on Query { a: x { " + next + " } b: x { " + next + " } }"
+	}
+	fanOut += " fragment F12 on Query { x }"
+
+	cases := []string{
+		fanOut,
+		"query { " + strings.Repeat("a ", MaxFields+1) + "}",
+	}
+
+	for _, query := range cases {
+		if _, err := parse(query); err == nil || !strings.Contains(err.Error(), "selects more than") {
+			t.Errorf("parse(%.40q...): fields error is expected but %v is found", query, err)
+		}
+	}
+
+	if _, err := parse("query { " + strings.Repeat("a ", MaxFields) + "}"); err != nil {
+		t.Errorf("parse: %d fields should be accepted but %s", MaxFields, err)
+	}
+}
@@ -0,0 +1,283 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+)
+
+// Scalars of the schema
+const (
+	scalarString  = "String"
+	scalarInt     = "Int"
+	scalarFloat   = "Float"
+	scalarBoolean = "Boolean"
+	scalarLong    = "Long"
+	scalarLink    = "Link"
+	scalarJSON    = "JSON"
+)
+
+// cidField is the field of the CID of a block
+const cidField = "_cid"
+
+// typeNames maps the schema names to the GraphQL type names
+var typeNames = map[string]string{
+	"iscn":         "Kernel",
+	"rights":       "Rights",
+	"stakeholders": "Stakeholders",
+	"content":      "Content",
+	"entity":       "Entity",
+	"right":        "Right",
+	"stakeholder":  "Stakeholder",
+	"timeperiod":   "TimePeriod",
+}
+
+// ==================================================
+// Types
+// ==================================================
+
+// typeRef is a reference to a type, which is a named type or a list
+type typeRef struct {
+	name string
+	elem *typeRef
+
+	nonNull bool
+}
+
+func (t *typeRef) String() string {
+	res := t.name
+	if t.elem != nil {
+		res = fmt.Sprintf("[%s]", t.elem)
+	}
+
+	if t.nonNull {
+		res += "!"
+	}
+	return res
+}
+
+// field is a field of an object type, resolved by the key of ISCN object
+type field struct {
+	name string
+	typ  *typeRef
+}
+
+// objectType is an object type generated from an ISCN schema
+type objectType struct {
+	name   string
+	codec  uint64
+	fields []*field
+	byName map[string]*field
+
+	// isBlock is true if the objects of the type are IPLD blocks
+	isBlock bool
+}
+
+func (t *objectType) addField(f *field) {
+	if _, exist := t.byName[f.name]; exist {
+		return
+	}
+
+	t.fields = append(t.fields, f)
+	t.byName[f.name] = f
+}
+
+// ==================================================
+// Schema
+// ==================================================
+
+// Schema is a GraphQL schema generated from the registered ISCN schemas
+type Schema struct {
+	registry *block.Registry
+
+	types map[string]*objectType
+
+	// queries maps the query fields to the top level object types
+	queries map[string]*objectType
+}
+
+// NewSchema generates a GraphQL schema from the latest version of every ISCN
+// schema registered to the registry
+func NewSchema(r *block.Registry) (*Schema, error) {
+	s := &Schema{
+		registry: r,
+		types:    map[string]*objectType{},
+		queries:  map[string]*objectType{},
+	}
+
+	for _, codec := range r.Codecs() {
+		if !block.IsIscnObject(codec) {
+			continue
+		}
+
+		typ, err := s.blockType(codec)
+		if err != nil {
+			return nil, err
+		}
+
+		name := strings.ToLower(typ.name[:1]) + typ.name[1:]
+		s.queries[name] = typ
+	}
+
+	return s, nil
+}
+
+func typeName(schemaName string) string {
+	if name, ok := typeNames[schemaName]; ok {
+		return name
+	}
+	return strings.ToUpper(schemaName[:1]) + schemaName[1:]
+}
+
+// blockType returns the object type of the top level ISCN object of codec
+func (s *Schema) blockType(codec uint64) (*objectType, error) {
+	version, err := s.registry.LatestVersion(codec)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := s.registry.New(codec, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.objectType(obj, codec, true)
+}
+
+// objectType returns the object type of the ISCN object, the type is created
+// if it does not exist
+func (s *Schema) objectType(obj interface{}, codec uint64, isBlock bool) (*objectType, error) {
+	named, ok := obj.(interface{ GetName() string })
+	if !ok {
+		return nil, fmt.Errorf("GraphQL: '%T' has no schema name", obj)
+	}
+
+	name := typeName(named.GetName())
+	if typ, ok := s.types[name]; ok {
+		return typ, nil
+	}
+
+	schema, ok := obj.(interface{ GetSchema() []data.Data })
+	if !ok {
+		return nil, fmt.Errorf("GraphQL: '%T' has no schema", obj)
+	}
+
+	typ := &objectType{
+		name:    name,
+		codec:   codec,
+		fields:  []*field{},
+		byName:  map[string]*field{},
+		isBlock: isBlock,
+	}
+	// Register before creating the fields for recursive types
+	s.types[name] = typ
+
+	if isBlock {
+		typ.addField(&field{name: cidField, typ: &typeRef{name: scalarLink, nonNull: true}})
+		typ.addField(&field{name: data.ContextKey, typ: &typeRef{name: scalarString}})
+	}
+
+	for _, handler := range schema.GetSchema() {
+		// Fields are nullable as they may be absent in other versions
+		ref, err := s.typeRef(handler)
+		if err != nil {
+			return nil, err
+		}
+
+		typ.addField(&field{
+			name: handler.GetKey(),
+			typ:  ref,
+		})
+	}
+
+	return typ, nil
+}
+
+// typeRef returns the type of the value of a data handler
+func (s *Schema) typeRef(handler data.Data) (*typeRef, error) {
+	switch d := handler.(type) {
+	case *data.Number:
+		if d.GetType() == data.Int32T {
+			return &typeRef{name: scalarInt}, nil
+		}
+		return &typeRef{name: scalarLong}, nil
+	case *data.Float64:
+		return &typeRef{name: scalarFloat}, nil
+	case *data.Bool:
+		return &typeRef{name: scalarBoolean}, nil
+	case *data.Context:
+		return &typeRef{name: scalarString}, nil
+	case *data.Cid:
+		if d.GetCodec() == 0 {
+			return &typeRef{name: scalarLink}, nil
+		}
+
+		if _, err := s.registry.LatestVersion(d.GetCodec()); err != nil {
+			return &typeRef{name: scalarLink}, nil
+		}
+
+		typ, err := s.blockType(d.GetCodec())
+		if err != nil {
+			return nil, err
+		}
+		return &typeRef{name: typ.name}, nil
+	case *data.Array:
+		elem, err := s.typeRef(d.GetPrototype())
+		if err != nil {
+			return nil, err
+		}
+		return &typeRef{elem: elem}, nil
+	case *data.Object:
+		typ, err := s.objectType(d.GetPrototypeFunc()(), 0, false)
+		if err != nil {
+			return nil, err
+		}
+		return &typeRef{name: typ.name}, nil
+	case *content.Fingerprint:
+		return &typeRef{name: scalarString}, nil
+	case interface{ Get() string }, interface{ GetID() string }:
+		return &typeRef{name: scalarString}, nil
+	}
+
+	return &typeRef{name: scalarJSON}, nil
+}
+
+// SDL returns the schema in the GraphQL schema definition language
+func (s *Schema) SDL() string {
+	b := strings.Builder{}
+	for _, scalar := range []string{scalarLong, scalarLink, scalarJSON} {
+		fmt.Fprintf(&b, "scalar %s\n", scalar)
+	}
+
+	names := []string{}
+	for name := range s.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fmt.Fprintf(&b, "\ntype %s {\n", name)
+		for _, f := range s.types[name].fields {
+			fmt.Fprintf(&b, "  %s: %s\n", f.name, f.typ)
+		}
+		b.WriteString("}\n")
+	}
+
+	queries := []string{}
+	for name := range s.queries {
+		queries = append(queries, name)
+	}
+	sort.Strings(queries)
+
+	b.WriteString("\ntype Query {\n")
+	for _, name := range queries {
+		fmt.Fprintf(&b, "  %s(cid: Link!): %s\n", name, s.queries[name].name)
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
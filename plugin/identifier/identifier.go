@@ -0,0 +1,319 @@
+package identifier
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// ==================================================
+// Scheme
+// ==================================================
+
+// NormalizeFunc validates the syntax and the checksum of an identifier and
+// returns its normalized form
+type NormalizeFunc func(value string) (string, error)
+
+// Scheme is a scheme of identifiers, e.g. ORCID and ISNI
+type Scheme struct {
+	name      string
+	normalize NormalizeFunc
+}
+
+// Name returns the name of the scheme
+func (s *Scheme) Name() string {
+	return s.name
+}
+
+// Normalize validates the identifier and returns its normalized form, so
+// that the same identifier always produces the same block
+func (s *Scheme) Normalize(value string) (string, error) {
+	return s.normalize(value)
+}
+
+var schemeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+var schemesLock sync.RWMutex
+var schemes = map[string]*Scheme{}
+
+// RegisterScheme registers an identifier scheme
+func RegisterScheme(name string, normalize NormalizeFunc) error {
+	if !schemeNamePattern.MatchString(name) {
+		return fmt.Errorf("Identifier: invalid scheme name %q", name)
+	}
+
+	if normalize == nil {
+		return fmt.Errorf("Identifier: normalize function of %q is missing", name)
+	}
+
+	schemesLock.Lock()
+	defer schemesLock.Unlock()
+
+	if _, exist := schemes[name]; exist {
+		return fmt.Errorf("Identifier: scheme %q is already registered", name)
+	}
+
+	schemes[name] = &Scheme{
+		name:      name,
+		normalize: normalize,
+	}
+
+	return nil
+}
+
+// GetScheme returns the registered scheme of the name
+func GetScheme(name string) (*Scheme, error) {
+	schemesLock.RLock()
+	defer schemesLock.RUnlock()
+
+	scheme, ok := schemes[name]
+	if !ok {
+		return nil, fmt.Errorf("Identifier: unknown scheme %q", name)
+	}
+
+	return scheme, nil
+}
+
+// Schemes returns the names of all registered schemes
+func Schemes() []string {
+	schemesLock.RLock()
+	defer schemesLock.RUnlock()
+
+	names := make([]string, 0, len(schemes))
+	for name := range schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Normalize validates the identifier of the scheme and returns its
+// normalized form
+func Normalize(scheme string, value string) (string, error) {
+	s, err := GetScheme(scheme)
+	if err != nil {
+		return "", err
+	}
+
+	return s.Normalize(value)
+}
+
+func mustRegisterScheme(name string, normalize NormalizeFunc) {
+	if err := RegisterScheme(name, normalize); err != nil {
+		panic(err)
+	}
+}
+
+// ==================================================
+// Built-in schemes
+// ==================================================
+
+// Names of the built-in schemes
+const (
+	SchemeORCID    = "orcid"
+	SchemeISNI     = "isni"
+	SchemeWikidata = "wikidata"
+	SchemeDID      = "did"
+	SchemeBech32   = "bech32"
+	SchemeLikeCoin = "likecoin"
+)
+
+// LikeCoinPrefix is the URI prefix of the LikeCoin chain IDs
+const LikeCoinPrefix = "lcc://id/"
+
+// LikeCoinHRP is the human readable part of the LikeCoin chain addresses
+const LikeCoinHRP = "cosmos"
+
+// AddressLength is the length of the payload of a bech32 address in bytes
+const AddressLength = 20
+
+var (
+	orcidPattern    = regexp.MustCompile(`^[0-9]{4}-[0-9]{4}-[0-9]{4}-[0-9]{3}[0-9X]$`)
+	isniPattern     = regexp.MustCompile(`^[0-9]{15}[0-9X]$`)
+	wikidataPattern = regexp.MustCompile(`^Q[1-9][0-9]*$`)
+	didPattern      = regexp.MustCompile(
+		`^did:[a-z0-9]+:` +
+			`(?:(?:[A-Za-z0-9._-]|%[0-9A-Fa-f]{2})*:)*` +
+			`(?:[A-Za-z0-9._-]|%[0-9A-Fa-f]{2})+$`,
+	)
+)
+
+// normalizeORCID accepts "0000-0002-1825-0097" and its "https://orcid.org/"
+// URI, the check digit is verified
+func normalizeORCID(value string) (string, error) {
+	value = trimPrefixes(value, "https://orcid.org/", "http://orcid.org/")
+	value = strings.ToUpper(value)
+	if !orcidPattern.MatchString(value) {
+		return "", fmt.Errorf("Identifier: %q is not an ORCID iD", value)
+	}
+
+	if !verifyMod11_2(strings.Replace(value, "-", "", -1)) {
+		return "", fmt.Errorf("Identifier: the check digit of ORCID iD %q is wrong", value)
+	}
+
+	return value, nil
+}
+
+// normalizeISNI accepts 16 characters with or without spaces and its
+// "https://isni.org/isni/" URI, the check digit is verified
+func normalizeISNI(value string) (string, error) {
+	value = trimPrefixes(value, "https://isni.org/isni/", "http://isni.org/isni/", "ISNI")
+	value = strings.ToUpper(strings.Replace(value, " ", "", -1))
+	if !isniPattern.MatchString(value) {
+		return "", fmt.Errorf("Identifier: %q is not an ISNI", value)
+	}
+
+	if !verifyMod11_2(value) {
+		return "", fmt.Errorf("Identifier: the check digit of ISNI %q is wrong", value)
+	}
+
+	return value, nil
+}
+
+// normalizeWikidata accepts the item ID, e.g. "Q42", and its entity URI
+func normalizeWikidata(value string) (string, error) {
+	value = trimPrefixes(
+		value,
+		"http://www.wikidata.org/entity/",
+		"https://www.wikidata.org/wiki/",
+	)
+	if !wikidataPattern.MatchString(value) {
+		return "", fmt.Errorf("Identifier: %q is not a Wikidata item ID", value)
+	}
+
+	return value, nil
+}
+
+// normalizeDID accepts the DID syntax of DID Core, the method is not
+// resolved
+func normalizeDID(value string) (string, error) {
+	if !didPattern.MatchString(value) {
+		return "", fmt.Errorf("Identifier: %q is not a DID", value)
+	}
+
+	return value, nil
+}
+
+// normalizeBech32 accepts a bech32 address of any Cosmos SDK chain, the
+// checksum and the length of the payload are verified
+func normalizeBech32(value string) (string, error) {
+	if _, _, err := DecodeAddress(value); err != nil {
+		return "", err
+	}
+
+	return strings.ToLower(value), nil
+}
+
+// normalizeLikeCoin accepts a LikeCoin chain ID, i.e. "lcc://id/" and a
+// bech32 address, or the bare address, the checksum is verified
+func normalizeLikeCoin(value string) (string, error) {
+	address := strings.TrimPrefix(value, LikeCoinPrefix)
+	if _, _, err := DecodeAddress(address, LikeCoinHRP); err != nil {
+		return "", err
+	}
+
+	return LikeCoinPrefix + strings.ToLower(address), nil
+}
+
+// DecodeAddress decodes the bech32 address and verifies its checksum and
+// the length of its payload, the human readable part should be one of the
+// hrps if any is given
+func DecodeAddress(address string, hrps ...string) (string, []byte, error) {
+	hrp, words, err := bech32.Decode(address)
+	if err != nil {
+		return "", nil, fmt.Errorf("Identifier: %q is not a bech32 address (%s)", address, err)
+	}
+
+	if len(hrps) > 0 && !contains(hrps, hrp) {
+		return "", nil, fmt.Errorf(
+			"Identifier: the prefix of %q should be one of %q but %q is found",
+			address,
+			hrps,
+			hrp,
+		)
+	}
+
+	payload, err := bech32.ConvertBits(words, 5, 8, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("Identifier: %q is not a bech32 address (%s)", address, err)
+	}
+
+	if len(payload) != AddressLength {
+		return "", nil, fmt.Errorf(
+			"Identifier: the payload of %q should be %d bytes but %d is found",
+			address,
+			AddressLength,
+			len(payload),
+		)
+	}
+
+	return hrp, payload, nil
+}
+
+// EncodeAddress encodes the payload as a bech32 address with the human
+// readable part
+func EncodeAddress(hrp string, payload []byte) (string, error) {
+	if len(payload) != AddressLength {
+		return "", fmt.Errorf(
+			"Identifier: the payload should be %d bytes but %d is found",
+			AddressLength,
+			len(payload),
+		)
+	}
+
+	words, err := bech32.ConvertBits(payload, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("Identifier: cannot encode the payload (%s)", err)
+	}
+
+	return bech32.Encode(hrp, words)
+}
+
+// verifyMod11_2 verifies the ISO 7064 MOD 11-2 check digit of ORCID iDs and
+// ISNIs
+func verifyMod11_2(digits string) bool {
+	total := 0
+	for _, r := range digits[:len(digits)-1] {
+		total = (total + int(r-'0')) * 2
+	}
+
+	check := (12 - total%11) % 11
+	expected := byte('0' + check)
+	if check == 10 {
+		expected = 'X'
+	}
+
+	return digits[len(digits)-1] == expected
+}
+
+func trimPrefixes(value string, prefixes ...string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return value[len(prefix):]
+		}
+	}
+	return value
+}
+
+func contains(list []string, s string) bool {
+	for _, elem := range list {
+		if elem == s {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	mustRegisterScheme(SchemeORCID, normalizeORCID)
+	mustRegisterScheme(SchemeISNI, normalizeISNI)
+	mustRegisterScheme(SchemeWikidata, normalizeWikidata)
+	mustRegisterScheme(SchemeDID, normalizeDID)
+	mustRegisterScheme(SchemeBech32, normalizeBech32)
+	mustRegisterScheme(SchemeLikeCoin, normalizeLikeCoin)
+}
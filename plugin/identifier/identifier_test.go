@@ -0,0 +1,146 @@
+package identifier
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVerifyMod11_2(t *testing.T) {
+	cases := []string{
+		"0000000218250097",
+		"000000021694233X",
+		"0000000151093700",
+		"000000012281955X",
+		"0000000121032683",
+	}
+
+	for _, digits := range cases {
+		if !verifyMod11_2(digits) {
+			t.Errorf("verifyMod11_2(%q): the check digit is correct", digits)
+		}
+
+		// Any single digit changed is detected
+		for i := 0; i < len(digits); i++ {
+			for _, r := range "0123456789X" {
+				if byte(r) == digits[i] || (r == 'X' && i != len(digits)-1) {
+					continue
+				}
+
+				corrupted := digits[:i] + string(r) + digits[i+1:]
+				if verifyMod11_2(corrupted) {
+					t.Errorf("verifyMod11_2(%q): the corrupted digit is not detected", corrupted)
+				}
+			}
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		scheme   string
+		value    string
+		expected string
+	}{
+		{SchemeORCID, "0000-0002-1825-0097", "0000-0002-1825-0097"},
+		{SchemeORCID, "https://orcid.org/0000-0002-1694-233X", "0000-0002-1694-233X"},
+		{SchemeORCID, "0000-0002-1694-233x", "0000-0002-1694-233X"},
+		{SchemeORCID, "0000-0002-1825-0098", ""},
+		{SchemeORCID, "0000-0002-1825-0079", ""},
+		{SchemeORCID, "0000-0002-1694-2330", ""},
+		{SchemeORCID, "0000000218250097", ""},
+		{SchemeISNI, "0000 0001 2281 955X", "000000012281955X"},
+		{SchemeISNI, "https://isni.org/isni/0000000121032683", "0000000121032683"},
+		{SchemeISNI, "0000 0001 2103 2684", ""},
+		{SchemeISNI, "0000 0001 2281 9551", ""},
+		{SchemeISNI, "0000 0001 2103 268", ""},
+		{SchemeWikidata, "Q42", "Q42"},
+		{SchemeWikidata, "http://www.wikidata.org/entity/Q42", "Q42"},
+		{SchemeWikidata, "https://www.wikidata.org/wiki/Q42", "Q42"},
+		{SchemeWikidata, "Q042", ""},
+		{SchemeWikidata, "P31", ""},
+		{SchemeDID, "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK", "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK"},
+		{SchemeDID, "did:web:example.com:user%3Aalice", "did:web:example.com:user%3Aalice"},
+		{SchemeDID, "did:web:example.com:", ""},
+		{SchemeDID, "did:Web:example.com", ""},
+		{SchemeDID, "did:web:exa mple.com", ""},
+		{SchemeDID, "did:web:%zz", ""},
+		{SchemeDID, "did:example", ""},
+		{SchemeBech32, "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c", "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c"},
+		{SchemeBech32, "COSMOS1W508D6QEJXTDG4Y5R3ZARVARY0C5XW7K6AH60C", "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c"},
+		{SchemeBech32, "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60d", ""},
+		{SchemeBech32, "cosmos1W508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c", ""},
+		{SchemeBech32, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", ""},
+		{SchemeLikeCoin, "lcc://id/cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c", "lcc://id/cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c"},
+		{SchemeLikeCoin, "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c", "lcc://id/cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c"},
+		{SchemeLikeCoin, "lcc://id/cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60d", ""},
+		{"unknown", "x", ""},
+	}
+
+	for _, c := range cases {
+		res, err := Normalize(c.scheme, c.value)
+		if c.expected == "" {
+			if err == nil {
+				t.Errorf("Normalize(%q, %q): error is expected but %q is found", c.scheme, c.value, res)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Normalize(%q, %q): %s", c.scheme, c.value, err)
+		} else if res != c.expected {
+			t.Errorf("Normalize(%q, %q): %q is expected but %q is found", c.scheme, c.value, c.expected, res)
+		}
+	}
+}
+
+func TestAddress(t *testing.T) {
+	address := "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c"
+
+	hrp, payload, err := DecodeAddress(address, "likecoin", LikeCoinHRP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hrp != LikeCoinHRP || len(payload) != AddressLength {
+		t.Fatalf("DecodeAddress: %q and %d bytes are found", hrp, len(payload))
+	}
+
+	if _, _, err := DecodeAddress(address, "likecoin"); err == nil || !strings.Contains(err.Error(), "prefix") {
+		t.Errorf("DecodeAddress: prefix error is expected but %v is found", err)
+	}
+
+	encoded, err := EncodeAddress(hrp, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if encoded != address {
+		t.Errorf("EncodeAddress: %q is expected but %q is found", address, encoded)
+	}
+
+	if _, err := EncodeAddress(hrp, payload[:19]); err == nil {
+		t.Errorf("EncodeAddress: the short payload is accepted")
+	}
+}
+
+func TestRegisterScheme(t *testing.T) {
+	normalize := func(value string) (string, error) {
+		return strings.ToLower(value), nil
+	}
+
+	if err := RegisterScheme(SchemeORCID, normalize); err == nil {
+		t.Errorf("RegisterScheme: the built-in scheme is replaced")
+	}
+
+	if err := RegisterScheme("Invalid Name", normalize); err == nil {
+		t.Errorf("RegisterScheme: the invalid name is accepted")
+	}
+
+	if err := RegisterScheme("test-scheme", normalize); err != nil {
+		t.Fatal(err)
+	}
+
+	if res, err := Normalize("test-scheme", "ABC"); err != nil || res != "abc" {
+		t.Errorf("Normalize: %q (%v) is found", res, err)
+	}
+}
@@ -0,0 +1,452 @@
+package index
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/fingerprint"
+
+	node "github.com/ipfs/go-ipld-format"
+)
+
+// Prefixes of the secondary indexes
+const (
+	prefixKernel      = "kernel"
+	prefixLink        = "link"
+	prefixEntity      = "entity"
+	prefixFingerprint = "fingerprint"
+	prefixTag         = "tag"
+	prefixHolder      = "holder"
+	prefixSubject     = "subject"
+
+	// The parts of the keys are separated by separator, in which separator
+	// and escape are escaped as escape followed by a byte, so that any string
+	// can be a part
+	separator = "\x00"
+	escape    = "\x01"
+)
+
+var (
+	escaper   = strings.NewReplacer(escape, escape+"\x02", separator, escape+"\x01")
+	unescaper = strings.NewReplacer(escape+"\x02", escape, escape+"\x01", separator)
+)
+
+// ==================================================
+// Index
+// ==================================================
+
+// Index maintains the secondary indexes of ISCN records
+type Index struct {
+	store Store
+}
+
+// KernelVersion is a version of an ISCN kernel
+type KernelVersion struct {
+	Version uint64
+	Cid     cid.Cid
+}
+
+// New creates an index backed by the store
+func New(store Store) *Index {
+	return &Index{
+		store: store,
+	}
+}
+
+// Close the index and the underlying store
+func (idx *Index) Close() error {
+	return idx.store.Close()
+}
+
+// Ingest adds a decoded ISCN block into the indexes, ingesting the same
+// block more than once is harmless
+func (idx *Index) Ingest(obj block.IscnObject) error {
+	switch obj.Cid().Type() {
+	case block.CodecISCN:
+		return idx.ingestKernel(obj)
+	case block.CodecStakeholders:
+		return idx.ingestStakeholders(obj)
+	case block.CodecContent:
+		return idx.ingestContent(obj)
+	case block.CodecRights:
+		return idx.ingestRights(obj)
+	case block.CodecEntity:
+		// Entities are referred by the other blocks, nothing to index
+		return nil
+	case block.CodecAttestation:
+		return idx.ingestAttestation(obj)
+	}
+
+	return fmt.Errorf("Index: %s is not an ISCN block", obj)
+}
+
+// KernelVersions returns all versions of the ISCN kernel with the ISCN ID in
+// ascending order of version
+func (idx *Index) KernelVersions(id string) ([]KernelVersion, error) {
+	res := []KernelVersion{}
+	err := idx.store.Iterate(prefix(prefixKernel, id), func(key []byte, _ []byte) error {
+		parts := split(key)
+		if len(parts) != 4 {
+			return fmt.Errorf("Index: corrupted key %q", key)
+		}
+
+		version, err := strconv.ParseUint(parts[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("Index: corrupted key %q", key)
+		}
+
+		c, err := cid.Decode(parts[3])
+		if err != nil {
+			return fmt.Errorf("Index: corrupted key %q", key)
+		}
+
+		res = append(res, KernelVersion{
+			Version: version,
+			Cid:     c,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// KernelsByLink returns the ISCN kernels linking to the rights, stakeholders
+// or content block
+func (idx *Index) KernelsByLink(c cid.Cid) ([]cid.Cid, error) {
+	return idx.cids(prefix(prefixLink, c.String()), 2)
+}
+
+// StakeholdersByEntity returns the stakeholders blocks listing the entity with
+// the role, or with any role if role is empty
+func (idx *Index) StakeholdersByEntity(entity cid.Cid, role string) ([]cid.Cid, error) {
+	parts := []string{prefixEntity, entity.String()}
+	if role != "" {
+		parts = append(parts, role)
+	}
+
+	return idx.cids(prefix(parts...), 3)
+}
+
+// KernelsByEntity returns the ISCN kernels listing the entity as a stakeholder
+// with the role, or with any role if role is empty
+func (idx *Index) KernelsByEntity(entity cid.Cid, role string) ([]cid.Cid, error) {
+	stakeholders, err := idx.StakeholdersByEntity(entity, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.kernelsByLinks(stakeholders)
+}
+
+// ContentByFingerprint returns the content blocks with the fingerprint,
+// fingerprints in different forms but of the same digest are matched
+func (idx *Index) ContentByFingerprint(value string) ([]cid.Cid, error) {
+	return idx.cids(prefix(prefixFingerprint, normalizeFingerprint(value)), 2)
+}
+
+// ContentByTag returns the content blocks with the tag
+func (idx *Index) ContentByTag(tag string) ([]cid.Cid, error) {
+	return idx.cids(prefix(prefixTag, tag), 2)
+}
+
+// RightsByHolder returns the rights blocks containing a right held by the entity
+func (idx *Index) RightsByHolder(holder cid.Cid) ([]cid.Cid, error) {
+	return idx.cids(prefix(prefixHolder, holder.String()), 2)
+}
+
+// KernelsByHolder returns the ISCN kernels containing a right held by the entity
+func (idx *Index) KernelsByHolder(holder cid.Cid) ([]cid.Cid, error) {
+	rights, err := idx.RightsByHolder(holder)
+	if err != nil {
+		return nil, err
+	}
+
+	return idx.kernelsByLinks(rights)
+}
+
+// AttestationsBySubject returns the attestation blocks signing the block
+func (idx *Index) AttestationsBySubject(subject cid.Cid) ([]cid.Cid, error) {
+	return idx.cids(prefix(prefixSubject, subject.String()), 2)
+}
+
+func (idx *Index) ingestKernel(obj block.IscnObject) error {
+	id, err := resolveString(obj, "id")
+	if err != nil {
+		return err
+	}
+
+	version, err := resolveUint64(obj, "version")
+	if err != nil {
+		return err
+	}
+
+	if err := idx.put(prefixKernel, id, fmt.Sprintf("%020d", version), obj.Cid().String()); err != nil {
+		return err
+	}
+
+	for _, key := range []string{"rights", "stakeholders", "content"} {
+		c, err := resolveLink(obj, key)
+		if err != nil {
+			return err
+		}
+
+		if err := idx.put(prefixLink, c.String(), obj.Cid().String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (idx *Index) ingestStakeholders(obj block.IscnObject) error {
+	stakeholders, err := resolveList(obj, "stakeholders")
+	if err != nil {
+		return err
+	}
+
+	for i, stakeholder := range stakeholders {
+		role, err := resolveString(stakeholder, "type")
+		if err != nil {
+			return fmt.Errorf("(Index %d) %s", i, err)
+		}
+
+		entity, err := resolveLink(stakeholder, "stakeholder")
+		if err != nil {
+			return fmt.Errorf("(Index %d) %s", i, err)
+		}
+
+		if err := idx.put(prefixEntity, entity.String(), role, obj.Cid().String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (idx *Index) ingestContent(obj block.IscnObject) error {
+	value, err := resolveString(obj, fingerprint.ContentKey)
+	if err != nil {
+		return err
+	}
+
+	if err := idx.put(prefixFingerprint, normalizeFingerprint(value), obj.Cid().String()); err != nil {
+		return err
+	}
+
+	tags, err := resolveList(obj, "tags")
+	if err != nil {
+		// Tags are optional
+		return nil
+	}
+
+	for i, t := range tags {
+		tag, ok := t.(string)
+		if !ok {
+			return fmt.Errorf("Index: (Index %d) tag should be 'string' but '%T' is found", i, t)
+		}
+
+		if err := idx.put(prefixTag, tag, obj.Cid().String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (idx *Index) ingestRights(obj block.IscnObject) error {
+	rights, err := resolveList(obj, "rights")
+	if err != nil {
+		return err
+	}
+
+	for i, right := range rights {
+		holder, err := resolveLink(right, "holder")
+		if err != nil {
+			return fmt.Errorf("(Index %d) %s", i, err)
+		}
+
+		if err := idx.put(prefixHolder, holder.String(), obj.Cid().String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (idx *Index) ingestAttestation(obj block.IscnObject) error {
+	subject, err := resolveLink(obj, "subject")
+	if err != nil {
+		return err
+	}
+
+	return idx.put(prefixSubject, subject.String(), obj.Cid().String())
+}
+
+func (idx *Index) put(parts ...string) error {
+	return idx.store.Put(key(parts...), []byte{})
+}
+
+// cids collects the CID at position n of the keys with the prefix
+func (idx *Index) cids(prefix []byte, n int) ([]cid.Cid, error) {
+	res := []cid.Cid{}
+	err := idx.store.Iterate(prefix, func(key []byte, _ []byte) error {
+		parts := split(key)
+		if len(parts) <= n {
+			return fmt.Errorf("Index: corrupted key %q", key)
+		}
+
+		c, err := cid.Decode(parts[n])
+		if err != nil {
+			return fmt.Errorf("Index: corrupted key %q", key)
+		}
+
+		res = append(res, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (idx *Index) kernelsByLinks(links []cid.Cid) ([]cid.Cid, error) {
+	res := []cid.Cid{}
+	seen := map[cid.Cid]struct{}{}
+	for _, link := range links {
+		kernels, err := idx.KernelsByLink(link)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, kernel := range kernels {
+			if _, ok := seen[kernel]; !ok {
+				seen[kernel] = struct{}{}
+				res = append(res, kernel)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func key(parts ...string) []byte {
+	escaped := make([]string, len(parts))
+	for i, part := range parts {
+		escaped[i] = escaper.Replace(part)
+	}
+	return []byte(strings.Join(escaped, separator))
+}
+
+func prefix(parts ...string) []byte {
+	return append(key(parts...), separator...)
+}
+
+func split(key []byte) []string {
+	res := []string{}
+	for _, part := range bytes.Split(key, []byte(separator)) {
+		res = append(res, unescaper.Replace(string(part)))
+	}
+	return res
+}
+
+// normalizeFingerprint converts the fingerprint into the "hash://" form if
+// possible, so that the same digest in different forms are indexed together
+func normalizeFingerprint(value string) string {
+	f, err := fingerprint.ParseAny(value)
+	if err != nil {
+		return value
+	}
+
+	uri, err := f.ToHashURI()
+	if err != nil {
+		return f.String()
+	}
+
+	return uri.String()
+}
+
+// ==================================================
+// Helpers to resolve the values of ISCN object
+// ==================================================
+
+type resolver interface {
+	Resolve(path []string) (interface{}, []string, error)
+}
+
+func resolve(obj interface{}, key string) (interface{}, error) {
+	r, ok := obj.(resolver)
+	if !ok {
+		return nil, fmt.Errorf("Index: '%T' cannot be resolved", obj)
+	}
+
+	value, _, err := r.Resolve([]string{key})
+	if err != nil {
+		return nil, fmt.Errorf("Index: cannot resolve %q (%s)", key, err)
+	}
+
+	return value, nil
+}
+
+func resolveString(obj interface{}, key string) (string, error) {
+	value, err := resolve(obj, key)
+	if err != nil {
+		return "", err
+	}
+
+	res, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("Index: %q should be 'string' but '%T' is found", key, value)
+	}
+
+	return res, nil
+}
+
+func resolveUint64(obj interface{}, key string) (uint64, error) {
+	value, err := resolve(obj, key)
+	if err != nil {
+		return 0, err
+	}
+
+	res, ok := value.(uint64)
+	if !ok {
+		return 0, fmt.Errorf("Index: %q should be 'uint64' but '%T' is found", key, value)
+	}
+
+	return res, nil
+}
+
+func resolveLink(obj interface{}, key string) (cid.Cid, error) {
+	value, err := resolve(obj, key)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	link, ok := value.(*node.Link)
+	if !ok {
+		return cid.Undef, fmt.Errorf("Index: %q should be a link but '%T' is found", key, value)
+	}
+
+	return link.Cid, nil
+}
+
+func resolveList(obj interface{}, key string) ([]interface{}, error) {
+	value, err := resolve(obj, key)
+	if err != nil {
+		return nil, err
+	}
+
+	res, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Index: %q should be an array but '%T' is found", key, value)
+	}
+
+	return res, nil
+}
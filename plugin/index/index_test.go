@@ -0,0 +1,80 @@
+package index
+
+import (
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+)
+
+func TestKeyParts(t *testing.T) {
+	cases := [][]string{
+		{"tag", "a", "b"},
+		{"tag", "a\x00x", "b"},
+		{"tag", "a\x01", "\x01\x02"},
+		{"tag", "", "\x00"},
+	}
+
+	for _, parts := range cases {
+		got := split(key(parts...))
+		if len(got) != len(parts) {
+			t.Errorf("split(key(%q)): %q is found", parts, got)
+			continue
+		}
+
+		for i := range parts {
+			if got[i] != parts[i] {
+				t.Errorf("split(key(%q)): %q is found", parts, got)
+				break
+			}
+		}
+	}
+}
+
+// A string containing the separator is indexed as is and does not break the
+// queries of the other strings
+func TestTagWithSeparator(t *testing.T) {
+	r := block.NewRegistry()
+	content.RegisterTo(r)
+	idx := New(NewMemoryStore())
+
+	newContent := func(tag string) block.IscnObject {
+		obj, err := r.Encode(block.CodecContent, 5, map[string]interface{}{
+			"version":     uint64(1),
+			"type":        "article",
+			"fingerprint": "hash://sha256/9564b85669d5e96ac969dd0161b8475bbced9e5999c6ec598da718a3045d6f2e",
+			"title":       "Title",
+			"tags":        []interface{}{tag},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := idx.Ingest(obj); err != nil {
+			t.Fatalf("Ingest: %s", err)
+		}
+		return obj
+	}
+
+	withNUL := newContent("a\x00x")
+	plain := newContent("a")
+
+	cases := []struct {
+		tag string
+		obj block.IscnObject
+	}{
+		{"a\x00x", withNUL},
+		{"a", plain},
+	}
+
+	for _, c := range cases {
+		res, err := idx.ContentByTag(c.tag)
+		if err != nil {
+			t.Fatalf("ContentByTag(%q): %s", c.tag, err)
+		}
+
+		if len(res) != 1 || !res[0].Equals(c.obj.Cid()) {
+			t.Errorf("ContentByTag(%q): [%s] is expected but %v is found", c.tag, c.obj.Cid(), res)
+		}
+	}
+}
@@ -0,0 +1,377 @@
+package index
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned when the key does not exist in the store
+var ErrNotFound = errors.New("Index: key is not found")
+
+// ==================================================
+// Store
+// ==================================================
+
+// Store is the interface of the key-value store backing an index
+type Store interface {
+	Get(key []byte) ([]byte, error)
+	Put(key []byte, value []byte) error
+	Delete(key []byte) error
+
+	// Iterate calls fn for every key with the prefix in ascending order of
+	// the key, the iteration stops at the first error returned by fn
+	Iterate(prefix []byte, fn func(key []byte, value []byte) error) error
+
+	Close() error
+}
+
+// ==================================================
+// MemoryStore
+// ==================================================
+
+// MemoryStore is an in-memory key-value store
+type MemoryStore struct {
+	lock sync.RWMutex
+	m    map[string][]byte
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates an in-memory key-value store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		m: map[string][]byte{},
+	}
+}
+
+// Get returns the value of the key
+func (s *MemoryStore) Get(key []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	value, ok := s.m[string(key)]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return append([]byte{}, value...), nil
+}
+
+// Put sets the value of the key
+func (s *MemoryStore) Put(key []byte, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.m[string(key)] = append([]byte{}, value...)
+	return nil
+}
+
+// Delete removes the key
+func (s *MemoryStore) Delete(key []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.m, string(key))
+	return nil
+}
+
+// Iterate calls fn for every key with the prefix in ascending order
+func (s *MemoryStore) Iterate(prefix []byte, fn func([]byte, []byte) error) error {
+	s.lock.RLock()
+	keys := []string{}
+	values := map[string][]byte{}
+	for key, value := range s.m {
+		if strings.HasPrefix(key, string(prefix)) {
+			keys = append(keys, key)
+			values[key] = value
+		}
+	}
+	s.lock.RUnlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := fn([]byte(key), append([]byte{}, values[key]...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close the store
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+// ==================================================
+// FileStore
+// ==================================================
+
+const (
+	opPut    byte = 1
+	opDelete byte = 2
+
+	maxRecordLength = 1 << 30
+)
+
+// rename replaces the log file with the compacted one, it is replaced in
+// tests to simulate failures
+var rename = os.Rename
+
+// FileStore is an on-disk key-value store, every change is appended to a log
+// file which is replayed into memory when the store is opened
+type FileStore struct {
+	*MemoryStore
+
+	lock   sync.Mutex
+	path   string
+	file   *os.File
+	writer *bufio.Writer
+}
+
+var _ Store = (*FileStore)(nil)
+
+// OpenFileStore opens or creates an on-disk key-value store at path
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		MemoryStore: NewMemoryStore(),
+		path:        path,
+	}
+
+	if err := s.replay(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s.file = file
+	s.writer = bufio.NewWriter(file)
+	return s, nil
+}
+
+// Put sets the value of the key
+func (s *FileStore) Put(key []byte, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.append(opPut, key, value); err != nil {
+		return err
+	}
+
+	return s.MemoryStore.Put(key, value)
+}
+
+// Delete removes the key
+func (s *FileStore) Delete(key []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if _, err := s.MemoryStore.Get(key); err == ErrNotFound {
+		return nil
+	}
+
+	if err := s.append(opDelete, key, nil); err != nil {
+		return err
+	}
+
+	return s.MemoryStore.Delete(key)
+}
+
+// Compact rewrites the log file with only the live keys
+func (s *FileStore) Compact() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	tmpPath := s.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(tmp)
+	err = s.MemoryStore.Iterate(nil, func(key []byte, value []byte) error {
+		return writeRecord(w, opPut, key, value)
+	})
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// The log file is closed before it is replaced, and reopened whether it
+	// is replaced or not, so that the store is still usable if it fails
+	err = s.file.Close()
+	if err == nil {
+		err = rename(tmpPath, s.path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+	}
+
+	file, openErr := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if openErr != nil {
+		if err == nil {
+			err = openErr
+		}
+		return err
+	}
+
+	s.file = file
+	s.writer = bufio.NewWriter(file)
+	return err
+}
+
+// Close the store
+func (s *FileStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+
+	return s.file.Close()
+}
+
+func (s *FileStore) append(op byte, key []byte, value []byte) error {
+	if err := writeRecord(s.writer, op, key, value); err != nil {
+		return err
+	}
+
+	if err := s.writer.Flush(); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
+
+// replay loads the log file into memory. A partial record at the end, which
+// is left by a crash in the middle of appending it, is truncated
+func (s *FileStore) replay() error {
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	r := &recordReader{Reader: bufio.NewReader(file)}
+	for {
+		offset := r.offset
+		op, key, value, err := readRecord(r)
+		if err == io.EOF {
+			return nil
+		} else if err == io.ErrUnexpectedEOF {
+			log.Printf("Index: truncating the partial record at %d of %q", offset, s.path)
+			return os.Truncate(s.path, offset)
+		} else if err != nil {
+			return fmt.Errorf("Index: corrupted store %q (%s)", s.path, err)
+		}
+
+		switch op {
+		case opPut:
+			s.MemoryStore.Put(key, value)
+		case opDelete:
+			s.MemoryStore.Delete(key)
+		default:
+			return fmt.Errorf("Index: corrupted store %q (unknown operation %d)", s.path, op)
+		}
+	}
+}
+
+func writeRecord(w io.Writer, op byte, key []byte, value []byte) error {
+	buffer := bytes.Buffer{}
+	buffer.WriteByte(op)
+
+	length := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(length, uint64(len(key)))
+	buffer.Write(length[:n])
+	buffer.Write(key)
+
+	n = binary.PutUvarint(length, uint64(len(value)))
+	buffer.Write(length[:n])
+	buffer.Write(value)
+
+	_, err := w.Write(buffer.Bytes())
+	return err
+}
+
+// recordReader counts the bytes read, which is the offset of the next record
+type recordReader struct {
+	*bufio.Reader
+	offset int64
+}
+
+func (r *recordReader) ReadByte() (byte, error) {
+	b, err := r.Reader.ReadByte()
+	if err == nil {
+		r.offset++
+	}
+	return b, err
+}
+
+func (r *recordReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+func readRecord(r *recordReader) (byte, []byte, []byte, error) {
+	op, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	key, err := readBytes(r)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	value, err := readBytes(r)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	return op, key, value, nil
+}
+
+func readBytes(r *recordReader) ([]byte, error) {
+	length, err := binary.ReadUvarint(r)
+	if err == io.EOF {
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+
+	if length > maxRecordLength {
+		return nil, fmt.Errorf("record length %d exceeds %d", length, maxRecordLength)
+	}
+
+	buffer := make([]byte, length)
+	if _, err := io.ReadFull(r, buffer); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	return buffer, nil
+}
@@ -0,0 +1,124 @@
+package index
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStoreTruncatesPartialRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A record torn by a crash in the middle of appending it
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte{opPut, 5, 'b', 'c'}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %s", err)
+	}
+
+	if value, err := s.Get([]byte("a")); err != nil || string(value) != "1" {
+		t.Errorf("Get: %q is expected but %q (%v) is found", "1", value, err)
+	}
+
+	if err := s.Put([]byte("b"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	truncated, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if truncated.Size() <= info.Size() {
+		t.Fatalf("the store should be appended after %d bytes", info.Size())
+	}
+
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore: %s", err)
+	}
+	defer s.Close()
+
+	if value, err := s.Get([]byte("b")); err != nil || string(value) != "2" {
+		t.Errorf("Get: %q is expected but %q (%v) is found", "2", value, err)
+	}
+}
+
+func TestFileStoreRejectsCorruptedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	if err := os.WriteFile(path, []byte{9, 1, 'a', 0}, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := OpenFileStore(path); err == nil {
+		t.Errorf("OpenFileStore: error is expected for an unknown operation")
+	}
+}
+
+func TestFileStoreCompactFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index")
+	s, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+
+	failure := errors.New("rename failed")
+	rename = func(string, string) error { return failure }
+	defer func() { rename = os.Rename }()
+
+	if err := s.Compact(); err != failure {
+		t.Fatalf("Compact: %v is expected but %v is found", failure, err)
+	}
+
+	if _, err := os.Stat(path + ".compact"); !os.IsNotExist(err) {
+		t.Errorf("Compact: the compacted file should be removed")
+	}
+
+	if err := s.Put([]byte("b"), []byte("2")); err != nil {
+		t.Fatalf("Put after a failed compaction: %s", err)
+	}
+	s.Close()
+
+	s, err = OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, expected := range map[string]string{"a": "1", "b": "2"} {
+		if value, err := s.Get([]byte(key)); err != nil || string(value) != expected {
+			t.Errorf("Get(%q): %q is expected but %q (%v) is found", key, expected, value, err)
+		}
+	}
+}
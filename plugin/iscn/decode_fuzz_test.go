@@ -0,0 +1,245 @@
+package iscn_test
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/attestation"
+	"github.com/likecoin/iscn-ipld/plugin/did"
+	"github.com/likecoin/iscn-ipld/plugin/iscn"
+
+	cbor "github.com/ipfs/go-ipld-cbor"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// newRegistry returns a registry with all ISCN objects registered
+func newRegistry() *block.Registry {
+	r := block.NewRegistry()
+	iscn.RegisterTo(r)
+	return r
+}
+
+func testCid(codec uint64, s string) cid.Cid {
+	h, err := mh.Sum([]byte(s), mh.SHA2_256, -1)
+	if err != nil {
+		panic(err)
+	}
+	return cid.NewCidV1(codec, h)
+}
+
+// samples returns the data of the ISCN objects of each codec, the data is
+// encoded to every version of the codec which accepts it
+func samples() map[uint64][]map[string]interface{} {
+	entity := testCid(block.CodecEntity, "entity")
+	terms := testCid(cid.Raw, "terms")
+	fingerprint := "hash://sha256/9564b85669d5e96ac969dd0161b8475bbced9e5999c6ec598da718a3045d6f2e"
+
+	right := map[string]interface{}{
+		"holder":    entity,
+		"type":      "License",
+		"terms":     terms,
+		"period":    map[string]interface{}{"from": "2020-01-01T00:00:00Z"},
+		"territory": "Global",
+	}
+
+	stakeholder := map[string]interface{}{
+		"type":        "Creator",
+		"stakeholder": entity,
+		"sharing":     uint64(100),
+	}
+
+	return map[uint64][]map[string]interface{}{
+		block.CodecISCN: {{
+			"id":           make([]byte, 32),
+			"timestamp":    "2020-01-01T12:34:56Z",
+			"version":      uint64(2),
+			"parent":       testCid(block.CodecISCN, "parent"),
+			"rights":       testCid(block.CodecRights, "rights"),
+			"stakeholders": testCid(block.CodecStakeholders, "stakeholders"),
+			"content":      testCid(block.CodecContent, "content"),
+		}},
+		block.CodecRights:       {{"rights": []interface{}{right}}},
+		block.CodecRight:        {right},
+		block.CodecStakeholders: {{"stakeholders": []interface{}{stakeholder}}},
+		block.CodecStakeholder:  {stakeholder},
+		block.CodecTimePeriod: {{
+			"from": "2020-01-01T00:00:00Z",
+			"to":   "2020-12-31T23:59:59Z",
+		}},
+		block.CodecContent: {
+			{
+				"version":     uint64(1),
+				"type":        "article",
+				"fingerprint": fingerprint,
+				"title":       "Title",
+				"tags":        []interface{}{"a", "b"},
+			},
+			{
+				"version":     uint64(2),
+				"parent":      testCid(block.CodecContent, "parent"),
+				"type":        "article",
+				"source":      "https://example.com/article",
+				"fingerprint": fingerprint,
+				"title":       map[string]interface{}{"en": "Title", "zh-Hant": "標題"},
+			},
+		},
+		block.CodecEntity: {
+			{
+				"id":   "lcc://id/cosmos1ajvs6w5pr7znzmyfvxv7c5ry39lqlmkp68zggw",
+				"name": "Name",
+			},
+			{
+				"did":         "did:key:z6MkhaXgBZDvotDkL5257faiztiGiC2QtKLGpbnnEGta2doK",
+				"identifiers": []interface{}{map[string]interface{}{"scheme": "orcid", "value": "0000-0002-1825-0097"}},
+			},
+		},
+	}
+}
+
+// seeds returns the raw data of valid and minimal objects of every codec and
+// version registered
+func seeds(tb testing.TB, r *block.Registry) map[uint64][][]byte {
+	res := map[uint64][][]byte{}
+	for codec, maps := range samples() {
+		versions, err := r.Versions(codec)
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		for _, version := range versions {
+			for _, m := range maps {
+				if obj, err := r.Encode(codec, version, m); err == nil {
+					res[codec] = append(res[codec], obj.RawData())
+				}
+			}
+		}
+	}
+
+	// Attestations are signed
+	_, priv, err := ed25519.GenerateKey(zeroReader{})
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	signer, err := did.NewEd25519Signer(priv)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	subject := testCid(block.CodecStakeholders, "stakeholders")
+	entity := testCid(block.CodecEntity, "entity")
+	payload, err := attestation.Payload(subject, entity, signer.PublicKey(), "")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	obj, err := r.Encode(block.CodecAttestation, 1, map[string]interface{}{
+		"subject":   subject,
+		"signer":    entity,
+		"key":       signer.PublicKey().Multibase(),
+		"signature": signature,
+	})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	res[block.CodecAttestation] = append(res[block.CodecAttestation], obj.RawData())
+
+	// Objects with the context only
+	for _, codec := range r.Codecs() {
+		versions, err := r.Versions(codec)
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		for _, version := range versions {
+			raw, err := cbor.DumpObject(map[string]interface{}{"context": version})
+			if err != nil {
+				tb.Fatal(err)
+			}
+			res[codec] = append(res[codec], raw)
+		}
+	}
+
+	return res
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+func TestSeedsCoverAllVersions(t *testing.T) {
+	r := newRegistry()
+	all := seeds(t, r)
+
+	for _, codec := range r.Codecs() {
+		versions, err := r.Versions(codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded := map[uint64]bool{}
+		for _, raw := range all[codec] {
+			h := sha256.Sum256(raw)
+			c := cid.NewCidV1(codec, append([]byte{mh.SHA2_256, 32}, h[:]...))
+			if obj, err := r.Decode(raw, c); err == nil {
+				decoded[obj.GetVersion()] = true
+			}
+		}
+
+		for _, version := range versions {
+			if !decoded[version] {
+				t.Errorf("No valid seed of codec 0x%x version %d", codec, version)
+			}
+		}
+	}
+}
+
+// FuzzDecode decodes arbitrary data as every codec, the decoder should
+// return an error rather than panic for any input
+func FuzzDecode(f *testing.F) {
+	r := newRegistry()
+	codecs := r.Codecs()
+
+	for i, codec := range codecs {
+		for _, raw := range seeds(f, r)[codec] {
+			f.Add(uint8(i), raw)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, i uint8, raw []byte) {
+		codec := codecs[int(i)%len(codecs)]
+
+		// The CID of the data, so that the decoding is not stopped by a
+		// mismatched CID
+		h := sha256.Sum256(raw)
+		c := cid.NewCidV1(codec, append([]byte{mh.SHA2_256, 32}, h[:]...))
+
+		obj, err := r.Decode(raw, c)
+		if errors.Is(err, block.ErrDecoderPanic) {
+			t.Fatalf("Decode(%x): %s", raw, err)
+		}
+
+		if err != nil {
+			return
+		}
+
+		// A decoded object should be presented in JSON
+		if _, err := obj.MarshalJSON(); err != nil {
+			t.Errorf("MarshalJSON(%x): %s", raw, err)
+		}
+	})
+}
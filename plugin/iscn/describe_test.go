@@ -0,0 +1,106 @@
+package iscn_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+var update = flag.Bool("update", false, "update the descriptor snapshots in testdata")
+
+// The descriptors of every version of each schema are pinned in
+// testdata/descriptors, run the tests with -update after changing a schema
+// on purpose
+func TestDescribeVersions(t *testing.T) {
+	r := newRegistry()
+
+	for _, codec := range r.Codecs() {
+		name, ok := r.SchemaName(codec)
+		if !ok {
+			t.Fatalf("No schema name of codec 0x%x", codec)
+		}
+
+		descs, err := r.DescribeVersions(codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		versions, err := r.Versions(codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(descs) != len(versions) {
+			t.Errorf("%s: %d versions are expected but %d are described", name, len(versions), len(descs))
+		}
+
+		for i, desc := range descs {
+			if desc.Codec != codec || desc.Schema != name || desc.Version != versions[i] {
+				t.Errorf(
+					"%s v%d: %s (0x%x) v%d is described",
+					name, versions[i],
+					desc.Schema, desc.Codec, desc.Version,
+				)
+			}
+
+			single, err := r.Describe(codec, desc.Version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			a, _ := json.Marshal(single)
+			b, _ := json.Marshal(desc)
+			if !bytes.Equal(a, b) {
+				t.Errorf("%s v%d: Describe and DescribeVersions differ", name, desc.Version)
+			}
+		}
+
+		snapshot, err := json.MarshalIndent(descs, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		snapshot = append(snapshot, '\n')
+
+		path := filepath.Join("testdata", "descriptors", name+".json")
+		if *update {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := ioutil.WriteFile(path, snapshot, 0644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+
+		expected, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(snapshot, expected) {
+			t.Errorf("%s: the descriptors differ from %s:\n%s", name, path, snapshot)
+		}
+	}
+}
+
+func TestDescribeUnknownVersion(t *testing.T) {
+	r := newRegistry()
+
+	latest, err := r.LatestVersion(block.CodecISCN)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Describe(block.CodecISCN, latest+1); err == nil {
+		t.Errorf("Describe: error is expected for version %d", latest+1)
+	}
+	if _, err := r.DescribeVersions(0x0300); err == nil {
+		t.Errorf("DescribeVersions: error is expected for an unknown codec")
+	}
+}
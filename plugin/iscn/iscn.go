@@ -2,6 +2,7 @@ package iscn
 
 import (
 	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/attestation"
 	"github.com/likecoin/iscn-ipld/plugin/block/content"
 	"github.com/likecoin/iscn-ipld/plugin/block/entity"
 	"github.com/likecoin/iscn-ipld/plugin/block/kernel"
@@ -14,17 +15,23 @@ import (
 	ipld "github.com/ipfs/go-ipld-format"
 )
 
-// Register all ISCN objects
+// Register all ISCN objects to the default registry
 func Register() {
-	kernel.Register()
-	rights.Register()
-	stakeholders.Register()
-	content.Register()
-	entity.Register()
+	RegisterTo(block.DefaultRegistry)
+}
+
+// RegisterTo registers all ISCN objects to the registry
+func RegisterTo(r *block.Registry) {
+	kernel.RegisterTo(r)
+	rights.RegisterTo(r)
+	stakeholders.RegisterTo(r)
+	content.RegisterTo(r)
+	entity.RegisterTo(r)
+	attestation.RegisterTo(r)
 
-	right.Register()
-	stakeholder.Register()
-	timeperiod.Register()
+	right.RegisterTo(r)
+	stakeholder.RegisterTo(r)
+	timeperiod.RegisterTo(r)
 }
 
 // RegisterBlockDecoders registers the decoder for different types of ISCN block
@@ -34,5 +41,6 @@ func RegisterBlockDecoders(decoder ipld.BlockDecoder) error {
 	decoder.Register(block.CodecStakeholders, block.DecodeBlock)
 	decoder.Register(block.CodecContent, block.DecodeBlock)
 	decoder.Register(block.CodecEntity, block.DecodeBlock)
+	decoder.Register(block.CodecAttestation, block.DecodeBlock)
 	return nil
 }
@@ -0,0 +1,90 @@
+package iscn_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// The samples of every version are decoded to the latest version, and the
+// migrated objects are decoded as they are
+func TestDecodeLatest(t *testing.T) {
+	r := newRegistry()
+
+	for codec, maps := range samples() {
+		versions, err := r.Versions(codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		latest, err := r.LatestVersion(codec)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, version := range versions {
+			for _, m := range maps {
+				obj, err := r.Encode(codec, version, m)
+				if err != nil {
+					// The sample is not accepted by the version
+					continue
+				}
+
+				res, report, err := r.DecodeLatest(obj.RawData(), obj.Cid())
+				if err != nil {
+					t.Errorf("0x%x v%d: %s", codec, version, err)
+					continue
+				}
+
+				if res.GetVersion() != latest {
+					t.Errorf("0x%x v%d: version %d is expected but %d is found", codec, version, latest, res.GetVersion())
+				}
+
+				if report.From != version || report.To != latest || !report.Source.Equals(obj.Cid()) {
+					t.Errorf(
+						"0x%x v%d: the report of %s v%d to v%d is found",
+						codec, version,
+						report.Source, report.From, report.To,
+					)
+				}
+
+				if version == latest && !res.Cid().Equals(obj.Cid()) {
+					t.Errorf("0x%x v%d: the latest version is re-encoded as %s", codec, version, res.Cid())
+				}
+
+				dec, err := r.Decode(res.RawData(), res.Cid())
+				if err != nil {
+					t.Errorf("0x%x v%d: %s", codec, version, err)
+					continue
+				}
+
+				if !dec.Cid().Equals(res.Cid()) {
+					t.Errorf("0x%x v%d: decoded CID %s is expected but %s is found", codec, version, res.Cid(), dec.Cid())
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeLatestInvalid(t *testing.T) {
+	r := newRegistry()
+
+	obj, err := r.Encode(block.CodecTimePeriod, 1, samples()[block.CodecTimePeriod][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw := append([]byte{}, obj.RawData()...)
+	raw[len(raw)-1] ^= 1
+	if _, _, err := r.DecodeLatest(raw, obj.Cid()); err == nil {
+		t.Errorf("DecodeLatest: error is expected for the mismatched data")
+	}
+
+	other := block.NewRegistry()
+	_, _, err = other.DecodeLatest(obj.RawData(), obj.Cid())
+	var codecErr *block.CodecError
+	if !errors.As(err, &codecErr) {
+		t.Errorf("DecodeLatest: CodecError is expected but %v is found", err)
+	}
+}
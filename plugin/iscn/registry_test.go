@@ -0,0 +1,73 @@
+package iscn_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/content"
+	"github.com/likecoin/iscn-ipld/plugin/block/data"
+)
+
+// The registry is used concurrently while the schemas, the extensions and
+// the settings are registered, run the tests with -race
+func TestConcurrentRegistry(t *testing.T) {
+	r := newRegistry()
+
+	objs := []block.IscnObject{}
+	for codec, maps := range samples() {
+		for _, m := range maps {
+			if obj, err := r.Encode(codec, 1, m); err == nil {
+				objs = append(objs, obj)
+			}
+		}
+	}
+
+	const n = 8
+	wg := sync.WaitGroup{}
+	errs := make(chan error, n*len(objs))
+
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			content.RegisterTo(r)
+			r.SetLimits(block.DefaultLimits)
+			r.SetCustomPolicy(block.PermissivePolicy())
+			err := r.RegisterExtension(block.Extension{
+				Prefix:     fmt.Sprintf("x-%d:", i),
+				Properties: []data.Data{data.NewString(fmt.Sprintf("x-%d:name", i), false)},
+			})
+			if err != nil {
+				errs <- err
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			for _, obj := range objs {
+				if _, err := r.Decode(obj.RawData(), obj.Cid()); err != nil {
+					errs <- err
+				}
+				if _, err := r.DescribeVersions(obj.Cid().Type()); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if extensions := r.Extensions(block.CodecContent); len(extensions) != n {
+		t.Errorf("%d extensions are expected but %d are found", n, len(extensions))
+	}
+}
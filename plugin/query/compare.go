@@ -0,0 +1,160 @@
+package query
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ipfs/go-cid"
+
+	node "github.com/ipfs/go-ipld-format"
+)
+
+// Layouts of the timestamps which can be compared, partial timestamps like
+// "2021" are compared as the beginning of the period
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func toNumber(value interface{}) (*big.Float, bool) {
+	switch v := value.(type) {
+	case int:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int8:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int16:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int32:
+		return new(big.Float).SetInt64(int64(v)), true
+	case int64:
+		return new(big.Float).SetInt64(v), true
+	case uint:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint8:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint16:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint32:
+		return new(big.Float).SetUint64(uint64(v)), true
+	case uint64:
+		return new(big.Float).SetUint64(v), true
+	case float32:
+		return new(big.Float).SetFloat64(float64(v)), true
+	case float64:
+		return new(big.Float).SetFloat64(v), true
+	}
+
+	return nil, false
+}
+
+func toCid(value interface{}) (cid.Cid, bool) {
+	switch v := value.(type) {
+	case cid.Cid:
+		return v, true
+	case *node.Link:
+		return v.Cid, true
+	case string:
+		c, err := cid.Decode(v)
+		if err != nil {
+			return cid.Undef, false
+		}
+		return c, true
+	}
+
+	return cid.Undef, false
+}
+
+// compare compares the value found in the ISCN object with the literal, the
+// result is -1, 0 or +1, ok is false if they are not comparable
+func compare(value interface{}, literal interface{}) (int, bool) {
+	if a, ok := toNumber(value); ok {
+		b, ok := toNumber(literal)
+		if !ok {
+			return 0, false
+		}
+		return a.Cmp(b), true
+	}
+
+	switch v := value.(type) {
+	case *node.Link, cid.Cid:
+		// Links are not ordered, the equality is checked by equal()
+		return 0, false
+	case bool:
+		b, ok := literal.(bool)
+		if !ok {
+			return 0, false
+		}
+		if v == b {
+			return 0, true
+		} else if !v {
+			return -1, true
+		}
+		return 1, true
+	case string:
+		b, ok := literal.(string)
+		if !ok {
+			return 0, false
+		}
+
+		if t1, ok := parseTime(v); ok {
+			if t2, ok := parseTime(b); ok {
+				switch {
+				case t1.Before(t2):
+					return -1, true
+				case t1.After(t2):
+					return 1, true
+				}
+				return 0, true
+			}
+		}
+
+		switch {
+		case v < b:
+			return -1, true
+		case v > b:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// equal checks whether the value found in the ISCN object equals the literal
+func equal(value interface{}, literal interface{}) bool {
+	switch value.(type) {
+	case *node.Link, cid.Cid:
+		a, _ := toCid(value)
+		b, ok := toCid(literal)
+		return ok && a.Equals(b)
+	}
+
+	res, ok := compare(value, literal)
+	return ok && res == 0
+}
+
+func formatLiteral(literal interface{}) string {
+	switch v := literal.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case cid.Cid:
+		return fmt.Sprintf("%q", v.String())
+	}
+
+	return fmt.Sprintf("%v", literal)
+}
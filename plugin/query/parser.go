@@ -0,0 +1,357 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ==================================================
+// Parser
+// ==================================================
+
+// Parse parses an expression of the query language:
+//
+//	expr       := and ("OR" and)*
+//	and        := unary ("AND" unary)*
+//	unary      := "NOT" unary | primary
+//	primary    := "(" expr ")"
+//	            | "ANY" path "(" expr ")"
+//	            | "EXISTS" path
+//	            | path operator literal
+//	operator   := "=" | "!=" | "<" | "<=" | ">" | ">="
+//	literal    := string | number | "true" | "false"
+//
+// A path is a list of property names separated by ".", "@" refers to the
+// current object. Keywords are case insensitive. For example:
+//
+//	content.tags = "photo" AND
+//	ANY rights.rights (type = "Reproduce" AND territory = "JP" AND period.from >= "2021")
+func Parse(s string) (Expr, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, fmt.Errorf("Query: unexpected %s at position %d", tok, tok.pos)
+	}
+
+	return expr, nil
+}
+
+// MustParse is like Parse but panics if the expression cannot be parsed
+func MustParse(s string) Expr {
+	expr, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	tok := p.peek()
+	return tok.kind == tokenIdent && strings.EqualFold(tok.text, keyword)
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	tok := p.next()
+	if tok.kind != kind || tok.text != text {
+		return fmt.Errorf("Query: %q is expected but %s is found at position %d", text, tok, tok.pos)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{expr}
+	for p.isKeyword("OR") {
+		p.next()
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return Or(exprs...), nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := []Expr{expr}
+	for p.isKeyword("AND") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, expr)
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return And(exprs...), nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not(expr), nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.peek()
+	switch {
+	case tok.kind == tokenSymbol && tok.text == "(":
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(tokenSymbol, ")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	case p.isKeyword("ANY"):
+		p.next()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(tokenSymbol, "("); err != nil {
+			return nil, err
+		}
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(tokenSymbol, ")"); err != nil {
+			return nil, err
+		}
+		return &anyExpr{path: path, expr: expr}, nil
+	case p.isKeyword("EXISTS"):
+		p.next()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		return &existsExpr{path: path}, nil
+	}
+
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+
+	op := p.next()
+	if op.kind != tokenOperator {
+		return nil, fmt.Errorf("Query: operator is expected but %s is found at position %d", op, op.pos)
+	}
+
+	literal, err := p.parseLiteral()
+	if err != nil {
+		return nil, err
+	}
+
+	return &comparison{
+		path:    path,
+		op:      Operator(op.text),
+		literal: literal,
+	}, nil
+}
+
+func (p *parser) parsePath() (Path, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return nil, fmt.Errorf("Query: path is expected but %s is found at position %d", tok, tok.pos)
+	}
+
+	return ParsePath(tok.text)
+}
+
+func (p *parser) parseLiteral() (interface{}, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenString:
+		return tok.text, nil
+	case tokenNumber:
+		if i, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return i, nil
+		}
+		if u, err := strconv.ParseUint(tok.text, 10, 64); err == nil {
+			return u, nil
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Query: invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return f, nil
+	case tokenIdent:
+		switch strings.ToLower(tok.text) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Query: literal is expected but %s is found at position %d", tok, tok.pos)
+}
+
+// ==================================================
+// Tokenizer
+// ==================================================
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOperator
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-.@", r)
+}
+
+func tokenize(s string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(r), pos: i})
+			i++
+		case r == '=' || r == '!' || r == '<' || r == '>':
+			start := i
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				i++
+			}
+
+			op := string(runes[start:i])
+			if op == "!" {
+				return nil, fmt.Errorf("Query: unexpected \"!\" at position %d", start)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, pos: start})
+		case r == '"':
+			start := i
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+
+			if i >= len(runes) {
+				return nil, fmt.Errorf("Query: unterminated string at position %d", start)
+			}
+			i++
+
+			value, err := strconv.Unquote(string(runes[start:i]))
+			if err != nil {
+				return nil, fmt.Errorf("Query: invalid string at position %d", start)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value, pos: start})
+		case unicode.IsDigit(r) || ((r == '-' || r == '+') && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+
+			// The exponent, e.g. "1e-5"
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				i++
+				if i < len(runes) && (runes[i] == '-' || runes[i] == '+') {
+					i++
+				}
+				for i < len(runes) && unicode.IsDigit(runes[i]) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), pos: start})
+		case isIdentRune(r):
+			start := i
+			for i < len(runes) && isIdentRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("Query: unexpected %q at position %d", r, i)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
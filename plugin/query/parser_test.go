@@ -0,0 +1,61 @@
+package query
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{`type = "Reproduce"`, `type = "Reproduce"`},
+		{`a.b != 1`, `a.b != 1`},
+		{`x > -2`, `x > -2`},
+		{`x > 1e-5`, `x > 1e-05`},
+		{`x <= 2.5E+3`, `x <= 2500`},
+		{`x >= 18446744073709551615`, `x >= 18446744073709551615`},
+		{`ok = TRUE`, `ok = true`},
+		{`a = 1 and b = 2 OR c = 3`, `((a = 1) AND (b = 2)) OR (c = 3)`},
+		{`a = 1 AND (b = 2 OR c = 3)`, `(a = 1) AND ((b = 2) OR (c = 3))`},
+		{`not a = 1`, `NOT (a = 1)`},
+		{`exists content.tags`, `EXISTS content.tags`},
+		{`ANY rights.rights (type = "Reproduce" AND @ != "x")`, `ANY rights.rights ((type = "Reproduce") AND (@ != "x"))`},
+		{`s = "a \"quoted\" string"`, `s = "a \"quoted\" string"`},
+	}
+
+	for _, c := range cases {
+		expr, err := Parse(c.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %s", c.query, err)
+			continue
+		}
+
+		if expr.String() != c.expected {
+			t.Errorf("Parse(%q): %q is expected but %q is found", c.query, c.expected, expr.String())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []string{
+		``,
+		`a`,
+		`a =`,
+		`a ! 1`,
+		`a = "unterminated`,
+		`a = 1 b = 2`,
+		`(a = 1`,
+		`ANY a b = 1`,
+		`a..b = 1`,
+		`a = 1e5x`,
+		`a = b`,
+		`a = #`,
+	}
+
+	for _, query := range cases {
+		if expr, err := Parse(query); err == nil {
+			t.Errorf("Parse(%q): error is expected but %s is found", query, expr)
+		}
+	}
+}
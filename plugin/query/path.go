@@ -0,0 +1,120 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	node "github.com/ipfs/go-ipld-format"
+)
+
+// ==================================================
+// Path
+// ==================================================
+
+// Path is a path to the values of an ISCN object, the elements are separated
+// by "." and links are followed when there are remaining elements
+type Path []string
+
+// ParsePath parses a dotted path like "rights.rights.type", "@" or an empty
+// string refers to the object itself
+func ParsePath(s string) (Path, error) {
+	if s == "" || s == "@" {
+		return Path{}, nil
+	}
+
+	path := Path(strings.Split(s, "."))
+	for _, elem := range path {
+		if elem == "" {
+			return nil, fmt.Errorf("Query: invalid path %q", s)
+		}
+	}
+
+	return path, nil
+}
+
+// String returns the dotted path
+func (p Path) String() string {
+	if len(p) == 0 {
+		return "@"
+	}
+	return strings.Join(p, ".")
+}
+
+type resolver interface {
+	Resolve(path []string) (interface{}, []string, error)
+}
+
+// Values returns all the values found at the path of obj, arrays are expanded
+// into their elements unless the next path element is an index
+func (p Path) Values(obj interface{}, loader block.Loader) ([]interface{}, error) {
+	return values(obj, p, loader)
+}
+
+func values(obj interface{}, path []string, loader block.Loader) ([]interface{}, error) {
+	if len(path) == 0 {
+		// Array-any semantics, an array matches when any element matches
+		if array, ok := obj.([]interface{}); ok {
+			res := []interface{}{}
+			for _, elem := range array {
+				vs, err := values(elem, path, loader)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, vs...)
+			}
+			return res, nil
+		}
+
+		return []interface{}{obj}, nil
+	}
+
+	first, rest := path[0], path[1:]
+	switch value := obj.(type) {
+	case *node.Link:
+		if loader == nil {
+			return nil, fmt.Errorf("Query: no loader to follow the link %s", value.Cid)
+		}
+
+		linked, err := loader.Load(value.Cid)
+		if err != nil {
+			return nil, err
+		}
+		return values(linked, path, loader)
+	case []interface{}:
+		if index, err := strconv.ParseUint(first, 10, 64); err == nil {
+			if index >= uint64(len(value)) {
+				return []interface{}{}, nil
+			}
+			return values(value[index], rest, loader)
+		}
+
+		res := []interface{}{}
+		for _, elem := range value {
+			vs, err := values(elem, path, loader)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, vs...)
+		}
+		return res, nil
+	case map[string]interface{}:
+		v, ok := value[first]
+		if !ok {
+			return []interface{}{}, nil
+		}
+		return values(v, rest, loader)
+	case resolver:
+		v, _, err := value.Resolve([]string{first})
+		if err != nil {
+			// The property does not exist
+			return []interface{}{}, nil
+		}
+		return values(v, rest, loader)
+	}
+
+	// Scalar value has no more path elements
+	return []interface{}{}, nil
+}
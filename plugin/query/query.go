@@ -0,0 +1,344 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+)
+
+// ==================================================
+// Expr
+// ==================================================
+
+// Expr is an expression evaluated against an ISCN object
+type Expr interface {
+	// Match evaluates the expression against obj, the loader is used to
+	// follow the links between ISCN objects
+	Match(obj interface{}, loader block.Loader) (bool, error)
+
+	String() string
+}
+
+// Filter returns the ISCN objects matching the expression
+func Filter(
+	expr Expr,
+	objs []block.IscnObject,
+	loader block.Loader,
+) ([]block.IscnObject, error) {
+	res := []block.IscnObject{}
+	for _, obj := range objs {
+		ok, err := expr.Match(obj, loader)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			res = append(res, obj)
+		}
+	}
+
+	return res, nil
+}
+
+// ==================================================
+// Comparison
+// ==================================================
+
+// Operator is the comparison operator
+type Operator string
+
+// Comparison operators
+const (
+	OpEq Operator = "="
+	OpNe Operator = "!="
+	OpLt Operator = "<"
+	OpLe Operator = "<="
+	OpGt Operator = ">"
+	OpGe Operator = ">="
+)
+
+type comparison struct {
+	path    Path
+	op      Operator
+	literal interface{}
+}
+
+var _ Expr = (*comparison)(nil)
+
+func newComparison(path string, op Operator, literal interface{}) Expr {
+	p, err := ParsePath(path)
+	if err != nil {
+		return &invalid{err: err}
+	}
+
+	return &comparison{
+		path:    p,
+		op:      op,
+		literal: literal,
+	}
+}
+
+// Eq matches when any value at the path equals the literal
+func Eq(path string, literal interface{}) Expr {
+	return newComparison(path, OpEq, literal)
+}
+
+// Ne matches when no value at the path equals the literal
+func Ne(path string, literal interface{}) Expr {
+	return newComparison(path, OpNe, literal)
+}
+
+// Lt matches when any value at the path is less than the literal
+func Lt(path string, literal interface{}) Expr {
+	return newComparison(path, OpLt, literal)
+}
+
+// Le matches when any value at the path is less than or equal to the literal
+func Le(path string, literal interface{}) Expr {
+	return newComparison(path, OpLe, literal)
+}
+
+// Gt matches when any value at the path is greater than the literal
+func Gt(path string, literal interface{}) Expr {
+	return newComparison(path, OpGt, literal)
+}
+
+// Ge matches when any value at the path is greater than or equal to the literal
+func Ge(path string, literal interface{}) Expr {
+	return newComparison(path, OpGe, literal)
+}
+
+// Between matches when any value at the path is within [from, to]
+func Between(path string, from interface{}, to interface{}) Expr {
+	p, err := ParsePath(path)
+	if err != nil {
+		return &invalid{err: err}
+	}
+
+	return Any(p.String(), And(Ge("", from), Le("", to)))
+}
+
+// Match evaluates the comparison
+func (e *comparison) Match(obj interface{}, loader block.Loader) (bool, error) {
+	values, err := e.path.Values(obj, loader)
+	if err != nil {
+		return false, err
+	}
+
+	if e.op == OpNe {
+		for _, value := range values {
+			if equal(value, e.literal) {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+
+	for _, value := range values {
+		if e.op == OpEq {
+			if equal(value, e.literal) {
+				return true, nil
+			}
+			continue
+		}
+
+		res, ok := compare(value, e.literal)
+		if !ok {
+			continue
+		}
+
+		switch e.op {
+		case OpLt:
+			ok = res < 0
+		case OpLe:
+			ok = res <= 0
+		case OpGt:
+			ok = res > 0
+		case OpGe:
+			ok = res >= 0
+		default:
+			return false, fmt.Errorf("Query: unknown operator %q", e.op)
+		}
+
+		if ok {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// String returns the expression in the query language
+func (e *comparison) String() string {
+	return fmt.Sprintf("%s %s %s", e.path, e.op, formatLiteral(e.literal))
+}
+
+// ==================================================
+// Logical expressions
+// ==================================================
+
+type andExpr []Expr
+
+// And matches when all expressions match
+func And(exprs ...Expr) Expr {
+	return andExpr(exprs)
+}
+
+// Match evaluates the expression
+func (e andExpr) Match(obj interface{}, loader block.Loader) (bool, error) {
+	for _, expr := range e {
+		ok, err := expr.Match(obj, loader)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// String returns the expression in the query language
+func (e andExpr) String() string {
+	return join(e, " AND ")
+}
+
+type orExpr []Expr
+
+// Or matches when any expression matches
+func Or(exprs ...Expr) Expr {
+	return orExpr(exprs)
+}
+
+// Match evaluates the expression
+func (e orExpr) Match(obj interface{}, loader block.Loader) (bool, error) {
+	for _, expr := range e {
+		ok, err := expr.Match(obj, loader)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+// String returns the expression in the query language
+func (e orExpr) String() string {
+	return join(e, " OR ")
+}
+
+type notExpr struct {
+	expr Expr
+}
+
+// Not matches when the expression does not match
+func Not(expr Expr) Expr {
+	return &notExpr{expr: expr}
+}
+
+// Match evaluates the expression
+func (e *notExpr) Match(obj interface{}, loader block.Loader) (bool, error) {
+	ok, err := e.expr.Match(obj, loader)
+	return !ok, err
+}
+
+// String returns the expression in the query language
+func (e *notExpr) String() string {
+	return fmt.Sprintf("NOT (%s)", e.expr)
+}
+
+func join(exprs []Expr, sep string) string {
+	res := []string{}
+	for _, expr := range exprs {
+		res = append(res, fmt.Sprintf("(%s)", expr))
+	}
+	return strings.Join(res, sep)
+}
+
+// ==================================================
+// Scoped expressions
+// ==================================================
+
+type anyExpr struct {
+	path Path
+	expr Expr
+}
+
+// Any matches when the expression matches any value at the path, the paths
+// in the expression are relative to the value, which is referred as "@"
+func Any(path string, expr Expr) Expr {
+	p, err := ParsePath(path)
+	if err != nil {
+		return &invalid{err: err}
+	}
+
+	return &anyExpr{
+		path: p,
+		expr: expr,
+	}
+}
+
+// Match evaluates the expression
+func (e *anyExpr) Match(obj interface{}, loader block.Loader) (bool, error) {
+	values, err := e.path.Values(obj, loader)
+	if err != nil {
+		return false, err
+	}
+
+	for _, value := range values {
+		ok, err := e.expr.Match(value, loader)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+// String returns the expression in the query language
+func (e *anyExpr) String() string {
+	return fmt.Sprintf("ANY %s (%s)", e.path, e.expr)
+}
+
+type existsExpr struct {
+	path Path
+}
+
+// Exists matches when there is any value at the path
+func Exists(path string) Expr {
+	p, err := ParsePath(path)
+	if err != nil {
+		return &invalid{err: err}
+	}
+
+	return &existsExpr{path: p}
+}
+
+// Match evaluates the expression
+func (e *existsExpr) Match(obj interface{}, loader block.Loader) (bool, error) {
+	values, err := e.path.Values(obj, loader)
+	if err != nil {
+		return false, err
+	}
+
+	return len(values) != 0, nil
+}
+
+// String returns the expression in the query language
+func (e *existsExpr) String() string {
+	return fmt.Sprintf("EXISTS %s", e.path)
+}
+
+// invalid reports the error of building an expression when it is evaluated
+type invalid struct {
+	err error
+}
+
+// Match returns the error
+func (e *invalid) Match(interface{}, block.Loader) (bool, error) {
+	return false, e.err
+}
+
+// String returns the error
+func (e *invalid) String() string {
+	return fmt.Sprintf("<invalid: %s>", e.err)
+}
@@ -0,0 +1,165 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	node "github.com/ipfs/go-ipld-format"
+	mh "github.com/multiformats/go-multihash"
+)
+
+func TestMatch(t *testing.T) {
+	obj := map[string]interface{}{
+		"title":   "Title",
+		"version": uint64(3),
+		"share":   int64(-2),
+		"ratio":   0.25,
+		"public":  true,
+		"tags":    []interface{}{"photo", "travel"},
+		"period": map[string]interface{}{
+			"from": "2021-04-01T00:00:00Z",
+			"to":   "2022",
+		},
+		"rights": []interface{}{
+			map[string]interface{}{"type": "Reproduce", "territory": "JP"},
+			map[string]interface{}{"type": "Display", "territory": "US"},
+		},
+	}
+
+	cases := []struct {
+		query    string
+		expected bool
+	}{
+		{`title = "Title"`, true},
+		{`title != "Title"`, false},
+		{`missing != "Title"`, true},
+		{`version = 3`, true},
+		{`version > 2.5`, true},
+		{`version < 3`, false},
+		{`share < 0`, true},
+		{`share >= -2`, true},
+		{`ratio > 2.5e-1`, false},
+		{`ratio >= 2.5e-1`, true},
+		{`ratio < 1E+0`, true},
+		{`version = "3"`, false},
+		{`public = true`, true},
+		{`public > false`, true},
+
+		// Arrays match when any element matches
+		{`tags = "travel"`, true},
+		{`tags != "travel"`, false},
+		{`tags.0 = "photo"`, true},
+		{`tags.1 = "photo"`, false},
+		{`tags.2 = "photo"`, false},
+		{`rights.type = "Display"`, true},
+		{`rights.territory = "JP" AND rights.type = "Display"`, true},
+		{`ANY rights (territory = "JP" AND type = "Display")`, false},
+		{`ANY rights (territory = "US" AND type = "Display")`, true},
+		{`ANY tags (@ = "photo")`, true},
+
+		// Timestamps are compared in time, partial ones from their beginning
+		{`period.from >= "2021"`, true},
+		{`period.from < "2021-04"`, false},
+		{`period.from = "2021-04-01T09:00:00+09:00"`, true},
+		{`period.to > "2021-12-31"`, true},
+		{`period.to = "2022-01-01"`, true},
+
+		{`EXISTS period.to`, true},
+		{`EXISTS period.until`, false},
+		{`NOT EXISTS title.x`, true},
+		{`title = "x" OR NOT version = 1`, true},
+	}
+
+	for _, c := range cases {
+		expr, err := Parse(c.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %s", c.query, err)
+			continue
+		}
+
+		ok, err := expr.Match(obj, nil)
+		if err != nil {
+			t.Errorf("%q: %s", c.query, err)
+			continue
+		}
+
+		if ok != c.expected {
+			t.Errorf("%q: %v is expected but %v is found", c.query, c.expected, ok)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	obj := map[string]interface{}{"years": []interface{}{"1999", "2021"}}
+
+	cases := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{"2020", "2022", true},
+		{"2000", "2020", false},
+		{"1999-01-01", "1999-01-01", true},
+	}
+
+	for _, c := range cases {
+		ok, err := Between("years", c.from, c.to).Match(obj, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if ok != c.expected {
+			t.Errorf("Between(%q, %q): %v is expected but %v is found", c.from, c.to, c.expected, ok)
+		}
+	}
+}
+
+// resolverFunc resolves a property by a map, like the ISCN objects
+type resolverFunc map[string]interface{}
+
+func (r resolverFunc) Resolve(path []string) (interface{}, []string, error) {
+	return r[path[0]], path[1:], nil
+}
+
+func TestMatchFollowsLinks(t *testing.T) {
+	h, err := mh.Sum([]byte("content"), mh.SHA2_256, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := cid.NewCidV1(block.CodecContent, h)
+
+	obj := map[string]interface{}{
+		"content": &node.Link{Cid: c},
+	}
+
+	expr := MustParse(`content = "` + c.String() + `"`)
+	if ok, err := expr.Match(obj, nil); err != nil || !ok {
+		t.Errorf("%s: the link should equal its CID (%v)", expr, err)
+	}
+
+	expr = MustParse(`content.title = "Title"`)
+	if _, err := expr.Match(obj, nil); err == nil {
+		t.Errorf("%s: the link is followed without a loader", expr)
+	}
+
+	loaded := []cid.Cid{}
+	loader := block.LoaderFunc(func(linked cid.Cid) (block.IscnObject, error) {
+		loaded = append(loaded, linked)
+		return nil, nil
+	})
+
+	// The loaded objects are resolved, an absent object has no values
+	if ok, err := expr.Match(obj, loader); err != nil || ok {
+		t.Errorf("%s: %v, %v", expr, ok, err)
+	}
+
+	if len(loaded) != 1 || !loaded[0].Equals(c) {
+		t.Errorf("%s is expected to be loaded but %v is found", c, loaded)
+	}
+
+	if ok, err := MustParse(`title = "Title"`).Match(resolverFunc{"title": "Title"}, nil); err != nil || !ok {
+		t.Errorf("The resolver is not matched (%v)", err)
+	}
+}
@@ -0,0 +1,225 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/block/kernel"
+	"github.com/likecoin/iscn-ipld/plugin/store"
+)
+
+// ==================================================
+// Request
+// ==================================================
+
+// requestError is the error of an invalid request other than the errors of
+// the ISCN objects
+type requestError struct {
+	err error
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{err: fmt.Errorf(format, args...)}
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// decodeObject reads a JSON object from the request body and converts it to
+// the data accepted by the ISCN object of the codec
+func decodeObject(r io.Reader, codec uint64) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var obj interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, badRequest("Invalid JSON: %s", err)
+	}
+
+	if decoder.More() {
+		return nil, badRequest("Invalid JSON: unexpected data after the object")
+	}
+
+	value, err := fromJSON(obj)
+	if err != nil {
+		return nil, &requestError{err: err}
+	}
+
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, badRequest("Invalid JSON: an object is expected")
+	}
+
+	// The ISCN ID is presented in the human readable form in JSON
+	if codec == block.CodecISCN {
+		if id, ok := m["id"].(string); ok {
+			raw, err := kernel.ParseID(id)
+			if err != nil {
+				return nil, &requestError{err: err}
+			}
+			m["id"] = raw
+		}
+	}
+
+	return m, nil
+}
+
+// fromJSON converts the decoded JSON value, numbers become int64, uint64 or
+// float64, {"/": "<cid>"} becomes a CID and {"/": {"bytes": "<base64>"}}
+// becomes a byte string
+func fromJSON(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return i, nil
+		}
+
+		if u, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return u, nil
+		}
+
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid number %q", v)
+		}
+		return f, nil
+	case map[string]interface{}:
+		if link, ok := v["/"]; ok && len(v) == 1 {
+			if b, ok := link.(map[string]interface{}); ok && len(b) == 1 {
+				return fromJSONBytes(b["bytes"])
+			}
+
+			s, ok := link.(string)
+			if !ok {
+				return nil, fmt.Errorf("Invalid link: string is expected but '%T' is found", link)
+			}
+
+			c, err := cid.Decode(strings.TrimPrefix(s, "/ipfs/"))
+			if err != nil {
+				return nil, fmt.Errorf("Invalid link %q: %s", s, err)
+			}
+			return c, nil
+		}
+
+		res := map[string]interface{}{}
+		for key, elem := range v {
+			conv, err := fromJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			res[key] = conv
+		}
+		return res, nil
+	case []interface{}:
+		res := []interface{}{}
+		for _, elem := range v {
+			conv, err := fromJSON(elem)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, conv)
+		}
+		return res, nil
+	}
+
+	return value, nil
+}
+
+// fromJSONBytes decodes the base64 of a byte string in the DAG-JSON form, the
+// padding is optional
+func fromJSONBytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("Invalid bytes: string is expected but '%T' is found", value)
+	}
+
+	b, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid bytes %q: %s", s, err)
+	}
+
+	return b, nil
+}
+
+// ==================================================
+// Response
+// ==================================================
+
+// Error codes of the response
+const (
+	codeBadRequest       = "bad_request"
+	codeNotFound         = "not_found"
+	codeMethodNotAllowed = "method_not_allowed"
+	codeValidationFailed = "validation_failed"
+	codeLimitExceeded    = "limit_exceeded"
+	codeInternal         = "internal_error"
+)
+
+// ErrorBody is the body of an error response
+type ErrorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+
+	Schema   string `json:"schema,omitempty"`
+	Version  uint64 `json:"version,omitempty"`
+	Property string `json:"property,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code string, err error) {
+	body := ErrorBody{
+		Code:    code,
+		Message: err.Error(),
+	}
+
+	var validationErr *block.ValidationError
+	if errors.As(err, &validationErr) {
+		body.Schema = validationErr.Schema
+		body.Version = validationErr.Version
+		body.Property = validationErr.Key
+	}
+
+	writeJSON(w, status, map[string]interface{}{
+		"error": body,
+	})
+}
+
+// writeISCNError classifies the error from encoding or decoding ISCN objects,
+// the errors not caused by the request, e.g. of the blockstore, are internal
+func writeISCNError(w http.ResponseWriter, err error) {
+	var validationErr *block.ValidationError
+	var reqErr *requestError
+	switch {
+	case errors.As(err, &validationErr):
+		writeError(w, http.StatusUnprocessableEntity, codeValidationFailed, err)
+	case errors.Is(err, block.ErrLimitExceeded):
+		writeError(w, http.StatusRequestEntityTooLarge, codeLimitExceeded, err)
+	case errors.Is(err, store.ErrNotFound):
+		writeError(w, http.StatusNotFound, codeNotFound, err)
+	case errors.As(err, &reqErr),
+		errors.Is(err, block.ErrUnknownCodec),
+		errors.Is(err, block.ErrUnsupportedVersion),
+		errors.Is(err, block.ErrDeprecatedVersion):
+		writeError(w, http.StatusBadRequest, codeBadRequest, err)
+	default:
+		writeError(w, http.StatusInternalServerError, codeInternal, err)
+	}
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/store"
+)
+
+func TestWriteISCNError(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{&block.ValidationError{Schema: "content", Version: 1, Err: errors.New("invalid")}, http.StatusUnprocessableEntity},
+		{&block.LimitError{Limit: "MaxBlockSize", Max: 1, Actual: 2}, http.StatusRequestEntityTooLarge},
+		{fmt.Errorf("loading: %w", store.ErrNotFound), http.StatusNotFound},
+		{badRequest("Invalid version %q", "x"), http.StatusBadRequest},
+		{&block.VersionError{Codec: block.CodecContent, Version: 9, Err: block.ErrUnsupportedVersion}, http.StatusBadRequest},
+		{errors.New("disk I/O error"), http.StatusInternalServerError},
+		{fmt.Errorf("%w: boom", block.ErrDecoderPanic), http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		writeISCNError(w, c.err)
+		if w.Code != c.status {
+			t.Errorf("writeISCNError(%q): %d is expected but %d is found", c.err, c.status, w.Code)
+		}
+	}
+}
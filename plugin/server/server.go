@@ -0,0 +1,354 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/graphql"
+	"github.com/likecoin/iscn-ipld/plugin/store"
+
+	blocks "github.com/ipfs/go-block-format"
+	node "github.com/ipfs/go-ipld-format"
+)
+
+const (
+	// MaxBodySize is the maximum size of the request body
+	MaxBodySize = 4 << 20
+
+	// ContentTypeCBOR is the content type of raw blocks
+	ContentTypeCBOR = "application/cbor"
+)
+
+// ==================================================
+// Server
+// ==================================================
+
+// Server is the HTTP API server of ISCN:
+//
+//	POST /v1/blocks/{schema}?version={version}    create a block from JSON
+//	GET  /v1/blocks/{cid}?format={json|cbor}      get a block
+//	GET  /v1/resolve/{cid}/{path}                 resolve a path of a block
+//	POST /v1/validate/{schema}?version={version}  validate JSON without storing
+//	GET  /v1/schemas                              list the registered schemas
+//	GET  /v1/schemas/{schema}?version={version}   describe a schema
+//	GET  /graphql, POST /graphql                  query ISCN records in GraphQL
+//
+// The latest version of the schema is used if version is omitted
+type Server struct {
+	registry   *block.Registry
+	blockstore store.Blockstore
+	loader     block.Loader
+	mux        *http.ServeMux
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// New creates an HTTP API server of the ISCN schemas registered to the
+// registry, backed by the blockstore
+func New(r *block.Registry, bs store.Blockstore) *Server {
+	s := &Server{
+		registry:   r,
+		blockstore: bs,
+		loader:     store.NewRegistryLoader(bs, r),
+		mux:        http.NewServeMux(),
+	}
+
+	s.mux.HandleFunc("/v1/blocks/", s.handleBlocks)
+	s.mux.HandleFunc("/v1/resolve/", s.handleResolve)
+	s.mux.HandleFunc("/v1/validate/", s.handleValidate)
+	s.mux.HandleFunc("/v1/schemas", s.handleSchemas)
+	s.mux.HandleFunc("/v1/schemas/", s.handleSchemas)
+	s.mux.Handle("/graphql", graphql.NewHandler(s.registry, s.loader))
+
+	return s
+}
+
+// Handle registers an extra handler for the pattern
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// ServeHTTP dispatches the request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleBlocks(w http.ResponseWriter, r *http.Request) {
+	arg := strings.TrimPrefix(r.URL.Path, "/v1/blocks/")
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		s.getBlock(w, r, arg)
+	case http.MethodPost:
+		s.postBlock(w, r, arg)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed,
+			fmt.Errorf("Method %s is not allowed", r.Method))
+	}
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed,
+			fmt.Errorf("Method %s is not allowed", r.Method))
+		return
+	}
+
+	obj, err := s.encode(w, r, strings.TrimPrefix(r.URL.Path, "/v1/validate/"))
+	if err != nil {
+		writeISCNError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"valid": true,
+		"cid":   obj.Cid().String(),
+	})
+}
+
+func (s *Server) postBlock(w http.ResponseWriter, r *http.Request, schema string) {
+	obj, err := s.encode(w, r, schema)
+	if err != nil {
+		writeISCNError(w, err)
+		return
+	}
+
+	b, err := blocks.NewBlockWithCid(obj.RawData(), obj.Cid())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, err)
+		return
+	}
+
+	if err := s.blockstore.Put(b); err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, err)
+		return
+	}
+
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, codeInternal, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"cid":   obj.Cid().String(),
+		"block": base64.StdEncoding.EncodeToString(obj.RawData()),
+		"data":  json.RawMessage(data),
+	})
+}
+
+func (s *Server) getBlock(w http.ResponseWriter, r *http.Request, arg string) {
+	c, err := cid.Decode(arg)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Errorf("Invalid CID %q: %s", arg, err))
+		return
+	}
+
+	b, err := s.blockstore.Get(c)
+	if err != nil {
+		writeISCNError(w, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Accept"), ContentTypeCBOR) {
+		format = "cbor"
+	}
+
+	switch format {
+	case "cbor", "raw":
+		w.Header().Set("Content-Type", ContentTypeCBOR)
+		w.WriteHeader(http.StatusOK)
+		w.Write(b.RawData())
+	case "", "json":
+		obj, err := s.registry.Decode(b.RawData(), b.Cid())
+		if err != nil {
+			writeISCNError(w, err)
+			return
+		}
+
+		data, err := obj.MarshalJSON()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, codeInternal, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	default:
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Errorf("Unknown format %q", format))
+	}
+}
+
+func (s *Server) handleResolve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed,
+			fmt.Errorf("Method %s is not allowed", r.Method))
+		return
+	}
+
+	path := []string{}
+	for _, elem := range strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/resolve/"), "/") {
+		if elem != "" {
+			path = append(path, elem)
+		}
+	}
+
+	if len(path) == 0 {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Errorf("CID is missing"))
+		return
+	}
+
+	c, err := cid.Decode(path[0])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, codeBadRequest, fmt.Errorf("Invalid CID %q: %s", path[0], err))
+		return
+	}
+
+	value, err := s.resolve(c, path[1:])
+	if err != nil {
+		writeISCNError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"value": value,
+	})
+}
+
+// resolve resolves the path from the block of the CID, following the links
+// to other blocks until the path is consumed
+func (s *Server) resolve(c cid.Cid, path []string) (interface{}, error) {
+	for {
+		obj, err := s.loader.Load(c)
+		if err != nil {
+			return nil, err
+		}
+
+		value, rest, err := obj.Resolve(path)
+		if err != nil {
+			return nil, &requestError{err: err}
+		}
+
+		if link, ok := value.(*node.Link); ok {
+			if len(rest) == 0 {
+				return map[string]string{"/": link.Cid.String()}, nil
+			}
+
+			c, path = link.Cid, rest
+			continue
+		}
+
+		if len(rest) != 0 {
+			return nil, badRequest("Cannot resolve %q", strings.Join(rest, "/"))
+		}
+
+		return value, nil
+	}
+}
+
+func (s *Server) handleSchemas(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, codeMethodNotAllowed,
+			fmt.Errorf("Method %s is not allowed", r.Method))
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/schemas"), "/")
+	if name == "" {
+		schemas := []map[string]interface{}{}
+		for _, codec := range s.registry.Codecs() {
+			schema, _ := s.registry.SchemaName(codec)
+			versions, err := s.registry.Versions(codec)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, codeInternal, err)
+				return
+			}
+
+			deprecated := []uint64{}
+			for _, version := range versions {
+				if s.registry.IsDeprecated(codec, version) {
+					deprecated = append(deprecated, version)
+				}
+			}
+
+			schemas = append(schemas, map[string]interface{}{
+				"codec":      codec,
+				"schema":     schema,
+				"versions":   versions,
+				"deprecated": deprecated,
+				"block":      block.IsIscnObject(codec),
+			})
+		}
+
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"schemas": schemas,
+		})
+		return
+	}
+
+	codec, ok := s.registry.LookupCodec(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, codeNotFound, fmt.Errorf("Unknown schema %q", name))
+		return
+	}
+
+	version, err := s.version(r, codec)
+	if err != nil {
+		writeISCNError(w, err)
+		return
+	}
+
+	desc, err := s.registry.Describe(codec, version)
+	if err != nil {
+		writeISCNError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, desc)
+}
+
+// version returns the version in the query, or the latest version of the
+// codec if it is omitted
+func (s *Server) version(r *http.Request, codec uint64) (uint64, error) {
+	v := r.URL.Query().Get("version")
+	if v == "" {
+		return s.registry.LatestVersion(codec)
+	}
+
+	version, err := strconv.ParseUint(v, 10, 64)
+	if err != nil || version == 0 {
+		return 0, badRequest("Invalid version %q", v)
+	}
+	return version, nil
+}
+
+// encode creates the ISCN object of the schema from the JSON request body
+func (s *Server) encode(
+	w http.ResponseWriter,
+	r *http.Request,
+	schema string,
+) (block.IscnObject, error) {
+	codec, ok := s.registry.LookupCodec(schema)
+	if !ok || !block.IsIscnObject(codec) {
+		return nil, badRequest("Unknown schema %q", schema)
+	}
+
+	version, err := s.version(r, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := decodeObject(http.MaxBytesReader(w, r.Body, MaxBodySize), codec)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.registry.Encode(codec, version, m)
+}
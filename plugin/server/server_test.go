@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/likecoin/iscn-ipld/plugin/block"
+	"github.com/likecoin/iscn-ipld/plugin/iscn"
+	"github.com/likecoin/iscn-ipld/plugin/store"
+)
+
+// newRegistry returns a registry with all ISCN objects registered
+func newRegistry() *block.Registry {
+	r := block.NewRegistry()
+	iscn.RegisterTo(r)
+	return r
+}
+
+func TestResolveIndex(t *testing.T) {
+	s := New(newRegistry(), store.NewMemoryBlockstore())
+
+	body := `{
+		"version": 1,
+		"type": "article",
+		"fingerprint": "hash://sha256/9564b85669d5e96ac969dd0161b8475bbced9e5999c6ec598da718a3045d6f2e",
+		"title": "Title",
+		"x-list": ["a", "b"]
+	}`
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/blocks/content", bytes.NewBufferString(body)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("%d: %s", w.Code, w.Body)
+	}
+
+	var created struct {
+		Cid string `json:"cid"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		path   string
+		status int
+	}{
+		{"x-list/1", http.StatusOK},
+		{"x-list/2", http.StatusBadRequest},
+		{"x-list/-1", http.StatusBadRequest},
+		{"x-list/a", http.StatusBadRequest},
+		{"x-list/18446744073709551616", http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/resolve/"+created.Cid+"/"+c.path, nil))
+		if w.Code != c.status {
+			t.Errorf("%s: %d is expected but %d is found (%s)", c.path, c.status, w.Code, w.Body)
+		}
+	}
+}
+
+// Each server uses its own registry, e.g. the custom property policy of one
+// server does not apply to the others
+func TestServerRegistry(t *testing.T) {
+	strict := newRegistry()
+	strict.SetCustomPolicy(block.StrictPolicy())
+
+	body := `{
+		"version": 1,
+		"type": "article",
+		"fingerprint": "hash://sha256/9564b85669d5e96ac969dd0161b8475bbced9e5999c6ec598da718a3045d6f2e",
+		"title": "Title",
+		"x-custom": "a"
+	}`
+
+	cases := []struct {
+		registry *block.Registry
+		status   int
+	}{
+		{newRegistry(), http.StatusOK},
+		{strict, http.StatusUnprocessableEntity},
+		{block.NewRegistry(), http.StatusBadRequest},
+	}
+
+	for i, c := range cases {
+		s := New(c.registry, store.NewMemoryBlockstore())
+
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/validate/content", bytes.NewBufferString(body)))
+		if w.Code != c.status {
+			t.Errorf("Registry %d: %d is expected but %d is found (%s)", i, c.status, w.Code, w.Body)
+		}
+	}
+}
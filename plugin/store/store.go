@@ -0,0 +1,207 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+	"github.com/likecoin/iscn-ipld/plugin/block"
+
+	blocks "github.com/ipfs/go-block-format"
+)
+
+// ErrNotFound is returned when the block does not exist in the blockstore
+var ErrNotFound = errors.New("Blockstore: block is not found")
+
+// ==================================================
+// Blockstore
+// ==================================================
+
+// Blockstore is the interface of the storage of raw blocks
+type Blockstore interface {
+	Put(b blocks.Block) error
+	Get(c cid.Cid) (blocks.Block, error)
+	Has(c cid.Cid) (bool, error)
+}
+
+// NewLoader creates a loader decoding the ISCN objects from the blockstore
+// with the default registry
+func NewLoader(bs Blockstore) block.Loader {
+	return NewRegistryLoader(bs, block.DefaultRegistry)
+}
+
+// NewRegistryLoader creates a loader decoding the ISCN objects from the
+// blockstore with the registry
+func NewRegistryLoader(bs Blockstore, r *block.Registry) block.Loader {
+	return block.LoaderFunc(func(c cid.Cid) (block.IscnObject, error) {
+		b, err := bs.Get(c)
+		if err != nil {
+			return nil, err
+		}
+
+		return r.Decode(b.RawData(), b.Cid())
+	})
+}
+
+// verify checks the data against the CID
+func verify(data []byte, c cid.Cid) (blocks.Block, error) {
+	actual, err := c.Prefix().Sum(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !actual.Equals(c) {
+		return nil, fmt.Errorf("Blockstore: data of %s is corrupted", c)
+	}
+
+	return blocks.NewBlockWithCid(data, c)
+}
+
+// ==================================================
+// MemoryBlockstore
+// ==================================================
+
+// MemoryBlockstore is an in-memory blockstore
+type MemoryBlockstore struct {
+	lock   sync.RWMutex
+	blocks map[cid.Cid][]byte
+}
+
+var _ Blockstore = (*MemoryBlockstore)(nil)
+
+// NewMemoryBlockstore creates an in-memory blockstore
+func NewMemoryBlockstore() *MemoryBlockstore {
+	return &MemoryBlockstore{
+		blocks: map[cid.Cid][]byte{},
+	}
+}
+
+// Put stores the block
+func (bs *MemoryBlockstore) Put(b blocks.Block) error {
+	if _, err := verify(b.RawData(), b.Cid()); err != nil {
+		return err
+	}
+
+	bs.lock.Lock()
+	defer bs.lock.Unlock()
+
+	bs.blocks[b.Cid()] = append([]byte{}, b.RawData()...)
+	return nil
+}
+
+// Get returns the block of the CID
+func (bs *MemoryBlockstore) Get(c cid.Cid) (blocks.Block, error) {
+	bs.lock.RLock()
+	defer bs.lock.RUnlock()
+
+	data, ok := bs.blocks[c]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return blocks.NewBlockWithCid(append([]byte{}, data...), c)
+}
+
+// Has checks whether the block of the CID exists
+func (bs *MemoryBlockstore) Has(c cid.Cid) (bool, error) {
+	bs.lock.RLock()
+	defer bs.lock.RUnlock()
+
+	_, ok := bs.blocks[c]
+	return ok, nil
+}
+
+// ==================================================
+// FlatFileBlockstore
+// ==================================================
+
+// FlatFileBlockstore is a blockstore storing every block as a file, the files
+// are sharded into sub-directories by the last two characters of the CID
+type FlatFileBlockstore struct {
+	dir string
+}
+
+var _ Blockstore = (*FlatFileBlockstore)(nil)
+
+// NewFlatFileBlockstore creates a flat-file blockstore in the directory
+func NewFlatFileBlockstore(dir string) (*FlatFileBlockstore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &FlatFileBlockstore{
+		dir: dir,
+	}, nil
+}
+
+func (bs *FlatFileBlockstore) path(c cid.Cid) string {
+	name := c.String()
+	return filepath.Join(bs.dir, name[len(name)-2:], name)
+}
+
+// Put stores the block
+func (bs *FlatFileBlockstore) Put(b blocks.Block) error {
+	if _, err := verify(b.RawData(), b.Cid()); err != nil {
+		return err
+	}
+
+	path := bs.path(b.Cid())
+	if _, err := os.Stat(path); err == nil {
+		// Blocks are immutable, nothing to do
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".put-")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(b.RawData())
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
+
+// Get returns the block of the CID
+func (bs *FlatFileBlockstore) Get(c cid.Cid) (blocks.Block, error) {
+	data, err := ioutil.ReadFile(bs.path(c))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return verify(data, c)
+}
+
+// Has checks whether the block of the CID exists
+func (bs *FlatFileBlockstore) Has(c cid.Cid) (bool, error) {
+	_, err := os.Stat(bs.path(c))
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}